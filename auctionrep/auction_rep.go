@@ -2,19 +2,36 @@ package auctionrep
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
 	"github.com/cloudfoundry-incubator/runtime-schema/models"
 )
 
+// simulation-only: returned when the rep's delegate does not simulate
+var NotSimulated = errors.New("rep's delegate is not a simulation")
+
 type AuctionRep struct {
-	repGuid  string
-	delegate auctiontypes.AuctionRepDelegate
-	lock     *sync.Mutex
+	repGuid        string
+	delegate       auctiontypes.AuctionRepDelegate
+	reservationTTL time.Duration
+	scorer         BidScorer
+	packScorer     BidScorer
+	stopScorer     StopBidScorer
+	overcommit     auctiontypes.OvercommitFactors
+	limits         Limits
+	draining       bool
+	inFlightBids   int32
+	lock           *sync.Mutex
 }
 
 type StartInstanceScoreInfo struct {
+	Zone                       string
+	Attributes                 []string
+	ProcessGuids               []string
 	RemainingResources         auctiontypes.Resources
 	TotalResources             auctiontypes.Resources
 	NumInstancesForProcessGuid int
@@ -23,13 +40,59 @@ type StartInstanceScoreInfo struct {
 type StopIndexScoreInfo struct {
 	InstanceScoreInfo            StartInstanceScoreInfo
 	InstanceGuidsForProcessIndex []string
+	//empty unless the delegate knows when its instances started
+	InstanceStartTimes map[string]time.Time
+}
+
+// Config tunes a rep. The zero Config holds reservations forever, bids with the DefaultScorer,
+// overcommits nothing and takes on any amount of work.
+type Config struct {
+	//reservations the rep is not told to run within ReservationTTL are released; 0 keeps them forever
+	ReservationTTL time.Duration
+	//what the rep bids for its state; nil means DefaultScorer
+	Scorer BidScorer
+	//what the rep bids for instances auctioned with the pack strategy; nil packs with Scorer, see PackingScorerFor
+	PackScorer BidScorer
+	//what the rep bids to keep an instance; nil keeps the instances on the reps Scorer finds least loaded
+	StopScorer StopBidScorer
+	//the rep promises as much of each resource as the delegate has times its factor; nil overcommits nothing
+	Overcommit auctiontypes.OvercommitFactors
+	//the rep refuses work beyond its limits as busy
+	Limits Limits
 }
 
-func New(repGuid string, delegate auctiontypes.AuctionRepDelegate) *AuctionRep {
+func New(repGuid string, delegate auctiontypes.AuctionRepDelegate, config Config) *AuctionRep {
+	scorer := config.Scorer
+	if scorer == nil {
+		scorer = DefaultScorer
+	}
+
+	packScorer := config.PackScorer
+	if packScorer == nil {
+		packScorer = PackingScorerFor(scorer)
+	}
+
+	stopScorer := config.StopScorer
+	if stopScorer == nil {
+		stopScorer = LeastLoadedStopScorer{Scorer: scorer}
+	}
+
+	for name, factor := range config.Overcommit {
+		if factor <= 0 {
+			panic(fmt.Sprintf("auctionrep: overcommit factor for %s must be positive, got %g", name, factor))
+		}
+	}
+
 	return &AuctionRep{
-		repGuid:  repGuid,
-		delegate: delegate,
-		lock:     &sync.Mutex{},
+		repGuid:        repGuid,
+		delegate:       delegate,
+		reservationTTL: config.ReservationTTL,
+		scorer:         scorer,
+		packScorer:     packScorer,
+		stopScorer:     stopScorer,
+		overcommit:     config.Overcommit,
+		limits:         config.Limits,
+		lock:           &sync.Mutex{},
 	}
 }
 
@@ -38,71 +101,334 @@ func (rep *AuctionRep) Guid() string {
 }
 
 // must lock here; the publicly visible operations should be atomic
-func (rep *AuctionRep) BidForStartAuction(startAuctionInfo auctiontypes.StartAuctionInfo) (float64, error) {
+func (rep *AuctionRep) BidForStartAuction(startAuctionInfo auctiontypes.StartAuctionInfo) (auctiontypes.StartAuctionBid, error) {
+	bid := auctiontypes.StartAuctionBid{
+		Rep: rep.repGuid,
+	}
+
+	if !rep.admitBid() {
+		return bid, auctiontypes.RepBusy
+	}
+	defer rep.bidDone()
+
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
 
+	if rep.draining {
+		return bid, auctiontypes.RepDraining
+	}
+
+	_, err := rep.reapExpiredReservations()
+	if err != nil {
+		return bid, err
+	}
+
+	err = rep.admitReservation()
+	if err != nil {
+		return bid, err
+	}
+
 	repInstanceScoreInfo, err := rep.repInstanceScoreInfo(startAuctionInfo.ProcessGuid)
 	if err != nil {
-		return 0, err
+		return bid, err
+	}
+
+	bid.Zone = repInstanceScoreInfo.Zone
+	bid.NumInstancesForProcessGuid = repInstanceScoreInfo.NumInstancesForProcessGuid
+	if startAuctionInfo.Explain {
+		bid.Explanation = ExplainBid(startAuctionInfo, repInstanceScoreInfo)
 	}
 
 	err = rep.satisfiesConstraints(startAuctionInfo, repInstanceScoreInfo)
+	if err == auctiontypes.InsufficientResources && startAuctionInfo.Preempt {
+		bid.Victims, err = rep.victims(startAuctionInfo, repInstanceScoreInfo)
+	}
 	if err != nil {
-		return 0, err
+		return bid, err
 	}
 
-	return rep.startAuctionBid(repInstanceScoreInfo), nil
+	bid.Bid = rep.scoreBid(&bid, startAuctionInfo, repInstanceScoreInfo)
+
+	return bid, nil
 }
 
 // must lock here; the publicly visible operations should be atomic
-func (rep *AuctionRep) BidForStopAuction(stopAuctionInfo auctiontypes.StopAuctionInfo) (float64, []string, error) {
+func (rep *AuctionRep) BidForStopAuction(stopAuctionInfo auctiontypes.StopAuctionInfo) (auctiontypes.StopAuctionBid, error) {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
 
+	bid := auctiontypes.StopAuctionBid{
+		Rep: rep.repGuid,
+	}
+
+	_, err := rep.reapExpiredReservations()
+	if err != nil {
+		return bid, err
+	}
+
 	repStopIndexScoreInfo, err := rep.repStopIndexScoreInfo(stopAuctionInfo)
 	if err != nil {
-		return 0, nil, err
+		return bid, err
 	}
 
+	bid.Zone = repStopIndexScoreInfo.InstanceScoreInfo.Zone
+	bid.NumInstancesForProcessGuid = repStopIndexScoreInfo.InstanceScoreInfo.NumInstancesForProcessGuid
+
 	err = rep.isRunningProcessIndex(repStopIndexScoreInfo)
 	if err != nil {
-		return 0, nil, err
+		return bid, err
+	}
+
+	bid.Bid = rep.stopScorer.ScoreStop(repStopIndexScoreInfo)
+	bid.InstanceGuids = repStopIndexScoreInfo.InstanceGuidsForProcessIndex
+	if len(repStopIndexScoreInfo.InstanceStartTimes) > 0 {
+		bid.InstanceStartTimes = repStopIndexScoreInfo.InstanceStartTimes
 	}
 
-	return rep.startAuctionBid(repStopIndexScoreInfo.InstanceScoreInfo), repStopIndexScoreInfo.InstanceGuidsForProcessIndex, nil
+	return bid, nil
 }
 
 // must lock here; the publicly visible operations should be atomic
-func (rep *AuctionRep) RebidThenTentativelyReserve(startAuctionInfo auctiontypes.StartAuctionInfo) (float64, error) {
+func (rep *AuctionRep) RebidThenTentativelyReserve(startAuctionInfo auctiontypes.StartAuctionInfo) (auctiontypes.StartAuctionBid, error) {
+	bid := auctiontypes.StartAuctionBid{
+		Rep: rep.repGuid,
+	}
+
+	if !rep.admitBid() {
+		return bid, auctiontypes.RepBusy
+	}
+	defer rep.bidDone()
+
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
+	defer rep.recordReservationsHeld()
+
+	if rep.draining {
+		return bid, auctiontypes.RepDraining
+	}
+
+	_, err := rep.reapExpiredReservations()
+	if err != nil {
+		return bid, err
+	}
+
+	err = rep.admitReservation()
+	if err != nil {
+		return bid, err
+	}
 
 	repInstanceScoreInfo, err := rep.repInstanceScoreInfo(startAuctionInfo.ProcessGuid)
 	if err != nil {
-		return 0, err
+		return bid, err
+	}
+
+	bid.Zone = repInstanceScoreInfo.Zone
+	bid.NumInstancesForProcessGuid = repInstanceScoreInfo.NumInstancesForProcessGuid
+	if startAuctionInfo.Explain {
+		bid.Explanation = ExplainBid(startAuctionInfo, repInstanceScoreInfo)
 	}
 
+	//a preempting reservation holds the room its victims still take up, until they are stopped
 	err = rep.satisfiesConstraints(startAuctionInfo, repInstanceScoreInfo)
+	if err == auctiontypes.InsufficientResources && startAuctionInfo.Preempt {
+		bid.Victims, err = rep.victims(startAuctionInfo, repInstanceScoreInfo)
+	}
 	if err != nil {
-		return 0, err
+		return bid, err
 	}
 
-	bid := rep.startAuctionBid(repInstanceScoreInfo)
+	bid.Bid = rep.scoreBid(&bid, startAuctionInfo, repInstanceScoreInfo)
 
 	//then reserve
-	err = rep.delegate.Reserve(startAuctionInfo)
+	err = rep.reserve(startAuctionInfo)
 	if err != nil {
-		return 0, err
+		return bid, err
 	}
 
 	return bid, nil
 }
 
+// must lock here; the publicly visible operations should be atomic
+func (rep *AuctionRep) BidForBatchStartAuction(startAuctionInfos []auctiontypes.StartAuctionInfo) (auctiontypes.BatchStartAuctionBid, error) {
+	if !rep.admitBid() {
+		return auctiontypes.BatchStartAuctionBid{}, auctiontypes.RepBusy
+	}
+	defer rep.bidDone()
+
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	if rep.draining {
+		return auctiontypes.BatchStartAuctionBid{}, auctiontypes.RepDraining
+	}
+
+	_, err := rep.reapExpiredReservations()
+	if err != nil {
+		return auctiontypes.BatchStartAuctionBid{}, err
+	}
+
+	err = rep.admitReservation()
+	if err != nil {
+		return auctiontypes.BatchStartAuctionBid{}, err
+	}
+
+	zone, err := rep.delegate.Zone()
+	if err != nil {
+		return auctiontypes.BatchStartAuctionBid{}, err
+	}
+
+	attributes, err := rep.delegate.Attributes()
+	if err != nil {
+		return auctiontypes.BatchStartAuctionBid{}, err
+	}
+
+	processGuids, err := rep.delegate.ProcessGuids()
+	if err != nil {
+		return auctiontypes.BatchStartAuctionBid{}, err
+	}
+
+	remaining, total, err := rep.resources()
+	if err != nil {
+		return auctiontypes.BatchStartAuctionBid{}, err
+	}
+
+	nInstances := map[string]int{}
+	for _, startAuctionInfo := range startAuctionInfos {
+		if _, ok := nInstances[startAuctionInfo.ProcessGuid]; ok {
+			continue
+		}
+
+		nInstances[startAuctionInfo.ProcessGuid], err = rep.delegate.NumInstancesForProcessGuid(startAuctionInfo.ProcessGuid)
+		if err != nil {
+			return auctiontypes.BatchStartAuctionBid{}, err
+		}
+	}
+
+	//the auctioneer places against the snapshot, but only the rep knows how it scores
+	scores := map[string]float64{}
+	for _, startAuctionInfo := range startAuctionInfos {
+		scores[startAuctionInfo.InstanceGuid] = rep.startAuctionBid(startAuctionInfo, StartInstanceScoreInfo{
+			Zone:                       zone,
+			Attributes:                 attributes,
+			ProcessGuids:               processGuids,
+			RemainingResources:         remaining,
+			TotalResources:             total,
+			NumInstancesForProcessGuid: nInstances[startAuctionInfo.ProcessGuid],
+		})
+	}
+
+	return auctiontypes.BatchStartAuctionBid{
+		Rep:                         rep.repGuid,
+		Zone:                        zone,
+		Attributes:                  attributes,
+		ProcessGuids:                processGuids,
+		RemainingResources:          remaining,
+		TotalResources:              total,
+		NumInstancesForProcessGuids: nInstances,
+		Scores:                      scores,
+	}, nil
+}
+
+// must lock here; the publicly visible operations should be atomic
+func (rep *AuctionRep) TentativelyReserveBatch(startAuctionInfos []auctiontypes.StartAuctionInfo) []error {
+	errs := make([]error, len(startAuctionInfos))
+
+	if !rep.admitBid() {
+		for i := range errs {
+			errs[i] = auctiontypes.RepBusy
+		}
+		return errs
+	}
+	defer rep.bidDone()
+
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+	defer rep.recordReservationsHeld()
+
+	if rep.draining {
+		for i := range errs {
+			errs[i] = auctiontypes.RepDraining
+		}
+		return errs
+	}
+
+	_, err := rep.reapExpiredReservations()
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	admitted, err := rep.reservationsAdmitted()
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	for i, startAuctionInfo := range startAuctionInfos {
+		//reserve what the limit allows, in order
+		if admitted == 0 {
+			repBusy.Inc(rep.repGuid, "reservations")
+			errs[i] = auctiontypes.RepBusy
+			continue
+		}
+
+		repInstanceScoreInfo, err := rep.repInstanceScoreInfo(startAuctionInfo.ProcessGuid)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		err = rep.satisfiesConstraints(startAuctionInfo, repInstanceScoreInfo)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		errs[i] = rep.reserve(startAuctionInfo)
+		if errs[i] == nil && admitted > 0 {
+			admitted--
+		}
+	}
+
+	return errs
+}
+
+// must lock here; the publicly visible operations should be atomic
+// a draining rep refuses to bid for or reserve new instances; it still runs what it reserved, stops and bids to stop
+func (rep *AuctionRep) SetDraining(draining bool) {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	rep.setDraining(draining)
+}
+
+// must lock here; the publicly visible operations should be atomic
+func (rep *AuctionRep) Draining() bool {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	return rep.draining
+}
+
+// must lock here; the publicly visible operations should be atomic
+// reps reap as they bid; call this periodically to also reclaim room on reps nobody is asking
+func (rep *AuctionRep) ReapExpiredReservations() ([]auctiontypes.StartAuctionInfo, error) {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+	defer rep.recordReservationsHeld()
+
+	return rep.reapExpiredReservations()
+}
+
 // must lock here; the publicly visible operations should be atomic
 func (rep *AuctionRep) ReleaseReservation(startAuctionInfo auctiontypes.StartAuctionInfo) error {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
+	defer rep.recordReservationsHeld()
 
 	return rep.delegate.ReleaseReservation(startAuctionInfo)
 }
@@ -111,6 +437,7 @@ func (rep *AuctionRep) ReleaseReservation(startAuctionInfo auctiontypes.StartAuc
 func (rep *AuctionRep) Run(startAuction models.LRPStartAuction) error {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
+	defer rep.recordReservationsHeld()
 
 	return rep.delegate.Run(startAuction)
 }
@@ -119,14 +446,25 @@ func (rep *AuctionRep) Run(startAuction models.LRPStartAuction) error {
 func (rep *AuctionRep) Stop(stopInstance models.StopLRPInstance) error {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
+	defer rep.recordReservationsHeld()
 
 	return rep.delegate.Stop(stopInstance)
 }
 
 // simulation-only
+// counts the overcommit, like the bids
 func (rep *AuctionRep) TotalResources() auctiontypes.Resources {
 	totalResources, _ := rep.delegate.TotalResources()
-	return totalResources
+	if len(rep.overcommit) == 0 {
+		return totalResources
+	}
+	return rep.overcommit.Total(totalResources)
+}
+
+// simulation-only
+func (rep *AuctionRep) Zone() string {
+	zone, _ := rep.delegate.Zone()
+	return zone
 }
 
 // simulation-only
@@ -141,6 +479,22 @@ func (rep *AuctionRep) Reset() {
 		return
 	}
 	simDelegate.SetSimulatedInstances([]auctiontypes.SimulatedInstance{})
+	simDelegate.SetFailsToRun(false)
+	rep.setDraining(false)
+}
+
+// simulation-only
+// must lock here; the publicly visible operations should be atomic
+func (rep *AuctionRep) SetFailsToRun(failsToRun bool) error {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	simDelegate, ok := rep.delegate.(auctiontypes.SimulationAuctionRepDelegate)
+	if !ok {
+		return NotSimulated
+	}
+	simDelegate.SetFailsToRun(failsToRun)
+	return nil
 }
 
 // simulation-only
@@ -173,12 +527,22 @@ func (rep *AuctionRep) SimulatedInstances() []auctiontypes.SimulatedInstance {
 
 // private internals -- no locks here
 func (rep *AuctionRep) repInstanceScoreInfo(processGuid string) (StartInstanceScoreInfo, error) {
-	remaining, err := rep.delegate.RemainingResources()
+	zone, err := rep.delegate.Zone()
 	if err != nil {
 		return StartInstanceScoreInfo{}, err
 	}
 
-	total, err := rep.delegate.TotalResources()
+	attributes, err := rep.delegate.Attributes()
+	if err != nil {
+		return StartInstanceScoreInfo{}, err
+	}
+
+	processGuids, err := rep.delegate.ProcessGuids()
+	if err != nil {
+		return StartInstanceScoreInfo{}, err
+	}
+
+	remaining, total, err := rep.resources()
 	if err != nil {
 		return StartInstanceScoreInfo{}, err
 	}
@@ -189,12 +553,82 @@ func (rep *AuctionRep) repInstanceScoreInfo(processGuid string) (StartInstanceSc
 	}
 
 	return StartInstanceScoreInfo{
+		Zone:                       zone,
+		Attributes:                 attributes,
+		ProcessGuids:               processGuids,
 		RemainingResources:         remaining,
 		TotalResources:             total,
 		NumInstancesForProcessGuid: nInstances,
 	}, nil
 }
 
+// private internals -- no locks here
+// what the rep may still promise and may promise in all, counting the overcommit
+func (rep *AuctionRep) resources() (auctiontypes.Resources, auctiontypes.Resources, error) {
+	remaining, err := rep.delegate.RemainingResources()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total, err := rep.delegate.TotalResources()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(rep.overcommit) == 0 {
+		return remaining, total, nil
+	}
+
+	return rep.overcommit.Remaining(remaining, total), rep.overcommit.Total(total), nil
+}
+
+// private internals -- no locks here
+// release the reservations of auctioneers that never came back to run them
+func (rep *AuctionRep) reapExpiredReservations() ([]auctiontypes.StartAuctionInfo, error) {
+	reaped := []auctiontypes.StartAuctionInfo{}
+	if rep.reservationTTL == 0 {
+		return reaped, nil
+	}
+
+	reservations, err := rep.delegate.Reservations()
+	if err != nil {
+		return reaped, err
+	}
+
+	for _, reservation := range reservations {
+		if time.Since(reservation.ReservedAt) < rep.reservationTTL {
+			continue
+		}
+
+		err := rep.delegate.ReleaseReservation(reservation.StartAuctionInfo)
+		if err != nil {
+			return reaped, err
+		}
+		reaped = append(reaped, reservation.StartAuctionInfo)
+		reservationsExpired.Inc(rep.repGuid)
+	}
+
+	return reaped, nil
+}
+
+// private internals -- no locks here
+func (rep *AuctionRep) setDraining(draining bool) {
+	rep.draining = draining
+	if draining {
+		repDraining.Set(1, rep.repGuid)
+	} else {
+		repDraining.Set(0, rep.repGuid)
+	}
+}
+
+func (rep *AuctionRep) recordReservationsHeld() {
+	reservations, err := rep.delegate.Reservations()
+	if err != nil {
+		return
+	}
+	reservationsHeld.Set(float64(len(reservations)), rep.repGuid)
+}
+
 // private internals -- no locks here
 func (rep *AuctionRep) repStopIndexScoreInfo(stopAuctionInfo auctiontypes.StopAuctionInfo) (StopIndexScoreInfo, error) {
 	instanceScoreInfo, err := rep.repInstanceScoreInfo(stopAuctionInfo.ProcessGuid)
@@ -207,24 +641,73 @@ func (rep *AuctionRep) repStopIndexScoreInfo(stopAuctionInfo auctiontypes.StopAu
 		return StopIndexScoreInfo{}, err
 	}
 
+	startTimes := map[string]time.Time{}
+	startTimesDelegate, ok := rep.delegate.(auctiontypes.InstanceStartTimesAuctionRepDelegate)
+	if ok {
+		startTimes, err = startTimesDelegate.InstanceStartTimes(instanceGuids)
+		if err != nil {
+			return StopIndexScoreInfo{}, err
+		}
+	}
+
+	//the rep keeps the first: make it the oldest
+	sort.Stable(byStartTime{instanceGuids, startTimes})
+
 	return StopIndexScoreInfo{
 		InstanceScoreInfo:            instanceScoreInfo,
 		InstanceGuidsForProcessIndex: instanceGuids,
+		InstanceStartTimes:           startTimes,
 	}, nil
 }
 
+// private internals -- no locks here
+// never hold an instance twice, however many auctions ask for it
+func (rep *AuctionRep) reserve(startAuctionInfo auctiontypes.StartAuctionInfo) error {
+	instanceGuids, err := rep.delegate.InstanceGuidsForProcessGuidAndIndex(startAuctionInfo.ProcessGuid, startAuctionInfo.Index)
+	if err != nil {
+		return err
+	}
+
+	for _, instanceGuid := range instanceGuids {
+		if instanceGuid == startAuctionInfo.InstanceGuid {
+			return auctiontypes.DuplicateInstance
+		}
+	}
+
+	return rep.delegate.Reserve(startAuctionInfo)
+}
+
 // private internals -- no locks here
 func (rep *AuctionRep) satisfiesConstraints(startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) error {
-	remaining := repInstanceScoreInfo.RemainingResources
-	hasEnoughMemory := remaining.MemoryMB >= startAuctionInfo.MemoryMB
-	hasEnoughDisk := remaining.DiskMB >= startAuctionInfo.DiskMB
-	hasEnoughContainers := remaining.Containers > 0
+	return SatisfiesConstraints(startAuctionInfo, repInstanceScoreInfo)
+}
 
-	if hasEnoughMemory && hasEnoughDisk && hasEnoughContainers {
-		return nil
-	} else {
-		return auctiontypes.InsufficientResources
+// private internals -- no locks here
+// evict the lowest-priority instances first and, among those, the largest
+func (rep *AuctionRep) victims(startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) ([]auctiontypes.EvictableInstance, error) {
+	candidates, err := rep.delegate.EvictableInstances(startAuctionInfo.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(byEvictionOrder(candidates))
+
+	victims := []auctiontypes.EvictableInstance{}
+	for _, candidate := range candidates {
+		if SatisfiesConstraints(startAuctionInfo, repInstanceScoreInfo) == nil {
+			break
+		}
+
+		victims = append(victims, candidate)
+		repInstanceScoreInfo.RemainingResources = repInstanceScoreInfo.RemainingResources.Add(candidate.Demand())
+	}
+
+	err = SatisfiesConstraints(startAuctionInfo, repInstanceScoreInfo)
+	if err != nil {
+		return nil, err
 	}
+
+	return victims, nil
 }
 
 func (rep *AuctionRep) isRunningProcessIndex(repStopIndexScoreInfo StopIndexScoreInfo) error {
@@ -235,13 +718,174 @@ func (rep *AuctionRep) isRunningProcessIndex(repStopIndexScoreInfo StopIndexScor
 }
 
 // private internals -- no locks here
-func (rep *AuctionRep) startAuctionBid(repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+// the bid adds up the score, the affinity penalty and the cost of the victims; the explanation, if any, itemizes it
+func (rep *AuctionRep) scoreBid(bid *auctiontypes.StartAuctionBid, startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+	score := rep.startAuctionBid(startAuctionInfo, repInstanceScoreInfo)
+	affinityPenalty := AffinityPenalty(startAuctionInfo, repInstanceScoreInfo)
+	evictionCost := EvictionCost * float64(len(bid.Victims))
+
+	if bid.Explanation != nil {
+		bid.Explanation.Score = score
+		bid.Explanation.AffinityPenalty = affinityPenalty
+		bid.Explanation.EvictionCost = evictionCost
+	}
+
+	return score + affinityPenalty + evictionCost
+}
+
+// private internals -- no locks here
+func (rep *AuctionRep) startAuctionBid(startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+	if startAuctionInfo.Strategy == auctiontypes.PackStrategy {
+		return rep.packScorer.Score(repInstanceScoreInfo)
+	}
+	return rep.scorer.Score(repInstanceScoreInfo)
+}
+
+// SatisfiesConstraints reports whether a rep in the given state may run the instance and has room for it.
+// Batch auctioneers apply it to the snapshots returned by BidForBatchStartAuction.
+func SatisfiesConstraints(startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) error {
+	if !hasAttributes(repInstanceScoreInfo.Attributes, startAuctionInfo.RequiredAttributes) {
+		return auctiontypes.ConstraintNotSatisfied
+	}
+
+	for _, rule := range startAuctionInfo.AffinityRules {
+		if rule.Hard && breaksAffinityRule(rule, repInstanceScoreInfo) {
+			return auctiontypes.ConstraintNotSatisfied
+		}
+	}
+
+	if !repInstanceScoreInfo.RemainingResources.Covers(startAuctionInfo.Demand()) {
+		return auctiontypes.InsufficientResources
+	}
+
+	return nil
+}
+
+// ExplainBid lays out the state a rep bids from and which of the instance's constraints it satisfies;
+// the bid's terms are filled in once the rep has scored it
+func ExplainBid(startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) *auctiontypes.BidExplanation {
 	remaining := repInstanceScoreInfo.RemainingResources
-	total := repInstanceScoreInfo.TotalResources
+	constraints := []auctiontypes.ConstraintResult{}
+
+	for _, required := range startAuctionInfo.RequiredAttributes {
+		constraints = append(constraints, auctiontypes.ConstraintResult{
+			Constraint: "attribute " + required,
+			Satisfied:  hasAttributes(repInstanceScoreInfo.Attributes, []string{required}),
+		})
+	}
+
+	for _, rule := range startAuctionInfo.AffinityRules {
+		if !rule.Hard {
+			continue
+		}
+		constraint := "affinity with " + rule.ProcessGuid
+		if rule.Anti {
+			constraint = "anti-affinity with " + rule.ProcessGuid
+		}
+		constraints = append(constraints, auctiontypes.ConstraintResult{
+			Constraint: constraint,
+			Satisfied:  !breaksAffinityRule(rule, repInstanceScoreInfo),
+		})
+	}
+
+	demand := startAuctionInfo.Demand()
+	for _, name := range demand.Names() {
+		constraints = append(constraints, auctiontypes.ConstraintResult{
+			Constraint: "resource " + name,
+			Satisfied:  remaining[name] >= demand[name],
+		})
+	}
+
+	return &auctiontypes.BidExplanation{
+		RemainingResources:         remaining,
+		TotalResources:             repInstanceScoreInfo.TotalResources,
+		NumInstancesForProcessGuid: repInstanceScoreInfo.NumInstancesForProcessGuid,
+		Usage:                      remaining.Usage(repInstanceScoreInfo.TotalResources),
+		Constraints:                constraints,
+	}
+}
+
+// EvictionCost is added to a preempting bid for every instance it would evict,
+// so that a rep with room always beats one that has to make room.
+const EvictionCost = 1000.0
+
+// StartAuctionBid scores a rep in the given state with the DefaultScorer, or the DefaultPackingScorer
+// when the instance is to be packed, as a rep with the zero Config would; lower bids win.
+func StartAuctionBid(startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+	if startAuctionInfo.Strategy == auctiontypes.PackStrategy {
+		return DefaultPackingScorer.Score(repInstanceScoreInfo)
+	}
+	return DefaultScorer.Score(repInstanceScoreInfo)
+}
+
+// AffinityPenalty is added to the bid of a rep for every soft affinity rule the instance would break there.
+// Each broken rule costs as much as one more colocated instance of the process.
+func AffinityPenalty(startAuctionInfo auctiontypes.StartAuctionInfo, repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+	penalty := 0.0
+	for _, rule := range startAuctionInfo.AffinityRules {
+		if !rule.Hard && breaksAffinityRule(rule, repInstanceScoreInfo) {
+			penalty += 1
+		}
+	}
+
+	return penalty
+}
+
+func breaksAffinityRule(rule auctiontypes.AffinityRule, repInstanceScoreInfo StartInstanceScoreInfo) bool {
+	runsProcess := false
+	for _, processGuid := range repInstanceScoreInfo.ProcessGuids {
+		if processGuid == rule.ProcessGuid {
+			runsProcess = true
+			break
+		}
+	}
+
+	return runsProcess == rule.Anti
+}
+
+func hasAttributes(attributes []string, requiredAttributes []string) bool {
+	for _, required := range requiredAttributes {
+		found := false
+		for _, attribute := range attributes {
+			if attribute == required {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+type byEvictionOrder []auctiontypes.EvictableInstance
+
+func (a byEvictionOrder) Len() int      { return len(a) }
+func (a byEvictionOrder) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byEvictionOrder) Less(i, j int) bool {
+	if a[i].Priority != a[j].Priority {
+		return a[i].Priority < a[j].Priority
+	}
+	return a[i].MemoryMB > a[j].MemoryMB
+}
 
-	fractionUsedContainers := 1.0 - float64(remaining.Containers)/float64(total.Containers)
-	fractionUsedDisk := 1.0 - float64(remaining.DiskMB)/float64(total.DiskMB)
-	fractionUsedMemory := 1.0 - float64(remaining.MemoryMB)/float64(total.MemoryMB)
+// instances whose start time is unknown go last
+type byStartTime struct {
+	instanceGuids []string
+	startTimes    map[string]time.Time
+}
 
-	return ((fractionUsedContainers + fractionUsedDisk + fractionUsedMemory) / 3.0) + float64(repInstanceScoreInfo.NumInstancesForProcessGuid)
+func (a byStartTime) Len() int { return len(a.instanceGuids) }
+func (a byStartTime) Swap(i, j int) {
+	a.instanceGuids[i], a.instanceGuids[j] = a.instanceGuids[j], a.instanceGuids[i]
+}
+func (a byStartTime) Less(i, j int) bool {
+	iStartedAt, iKnown := a.startTimes[a.instanceGuids[i]]
+	jStartedAt, jKnown := a.startTimes[a.instanceGuids[j]]
+	if iKnown != jKnown {
+		return iKnown
+	}
+	return iStartedAt.Before(jStartedAt)
 }
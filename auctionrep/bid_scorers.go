@@ -0,0 +1,123 @@
+package auctionrep
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+// A BidScorer turns the state of a rep into its bid for an instance; lower bids win.
+// Affinity penalties and eviction costs are added on top of the score.
+type BidScorer interface {
+	Score(repInstanceScoreInfo StartInstanceScoreInfo) float64
+}
+
+// WeightedScorer takes the weighted average of the fractions of every resource the rep has in use
+// and adds Colocation for every instance of the process the rep already runs.
+// Resources missing from Weights weigh 1.
+type WeightedScorer struct {
+	Weights    map[string]float64
+	Colocation float64
+}
+
+func (s WeightedScorer) Score(repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+	return s.usage(repInstanceScoreInfo) + s.Colocation*float64(repInstanceScoreInfo.NumInstancesForProcessGuid)
+}
+
+func (s WeightedScorer) usage(repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+	remaining := repInstanceScoreInfo.RemainingResources
+	total := repInstanceScoreInfo.TotalResources
+
+	//in name order, so that equal reps bid exactly alike
+	weightedUsage, totalWeight := 0.0, 0.0
+	for _, name := range total.Names() {
+		if total[name] <= 0 {
+			continue
+		}
+
+		weight, ok := s.Weights[name]
+		if !ok {
+			weight = 1
+		}
+
+		fractionUsed := 1.0 - float64(remaining[name])/float64(total[name])
+		weightedUsage += weight * fractionUsed
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 0
+	}
+
+	return weightedUsage / totalWeight
+}
+
+// PackingScorer turns the weighted usage around so the fullest rep wins, consolidating instances onto as few reps as possible.
+// Colocated instances still cost Colocation each, so the instances of one process keep to different reps.
+type PackingScorer struct {
+	WeightedScorer
+}
+
+func (s PackingScorer) Score(repInstanceScoreInfo StartInstanceScoreInfo) float64 {
+	return 1.0 - s.usage(repInstanceScoreInfo) + s.Colocation*float64(repInstanceScoreInfo.NumInstancesForProcessGuid)
+}
+
+// DefaultScorer weighs every resource alike; each colocated instance costs as much as a full rep
+var DefaultScorer BidScorer = WeightedScorer{Colocation: 1}
+
+// DefaultPackingScorer scores the instances auctioned with the pack strategy
+var DefaultPackingScorer BidScorer = PackingScorer{WeightedScorer{Colocation: 1}}
+
+// PackingScorerFor is what a rep scoring with the given scorer bids under the pack strategy:
+// a WeightedScorer packs with its own weights and a PackingScorer already packs.
+// Any other scorer cannot be turned around, so its reps pack with the DefaultPackingScorer unless given a Config.PackScorer.
+func PackingScorerFor(scorer BidScorer) BidScorer {
+	switch s := scorer.(type) {
+	case WeightedScorer:
+		return PackingScorer{s}
+	case PackingScorer:
+		return s
+	default:
+		return DefaultPackingScorer
+	}
+}
+
+// the built-in scorers, by the name reps are configured with
+var bidScorers = map[string]BidScorer{
+	"default": DefaultScorer,
+	//memory is usually what runs out first
+	"memory_weighted": WeightedScorer{Weights: map[string]float64{auctiontypes.MemoryMB: 4}, Colocation: 1},
+	//a colocated instance outweighs breaking soft affinity rules
+	"colocation_averse": WeightedScorer{Colocation: 4},
+	//packs every instance, whatever strategy it is auctioned with
+	"packing": DefaultPackingScorer,
+}
+
+type UnknownBidScorerError struct {
+	BidScorer string
+}
+
+func (e UnknownBidScorerError) Error() string {
+	return fmt.Sprintf("unknown bid scorer %q", e.BidScorer)
+}
+
+// BidScorers returns the sorted names of the built-in scorers.
+func BidScorers() []string {
+	names := []string{}
+	for name := range bidScorers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func LookupBidScorer(name string) (BidScorer, error) {
+	scorer, ok := bidScorers[name]
+	if !ok {
+		return nil, UnknownBidScorerError{BidScorer: name}
+	}
+
+	return scorer, nil
+}
@@ -0,0 +1,66 @@
+package auctionrep
+
+import (
+	"sync/atomic"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+// Limits cap the work a rep takes on at once, so that one attractive rep does not pile up reservations
+// whose room its later bids no longer reflect. A rep at a limit refuses with auctiontypes.RepBusy, which
+// auctioneers take as "ask again later" rather than "no room". Zero means no limit.
+type Limits struct {
+	//reservations held and not yet run or released
+	MaxReservations int
+	//start bids and reservations being handled, counting those waiting their turn
+	MaxInFlightBids int
+}
+
+// admitBid counts the caller among the bids in flight unless the rep already has as many as it takes;
+// callers it admits must call bidDone when they are done
+func (rep *AuctionRep) admitBid() bool {
+	inFlight := atomic.AddInt32(&rep.inFlightBids, 1)
+	if rep.limits.MaxInFlightBids > 0 && int(inFlight) > rep.limits.MaxInFlightBids {
+		atomic.AddInt32(&rep.inFlightBids, -1)
+		repBusy.Inc(rep.repGuid, "in-flight-bids")
+		return false
+	}
+	return true
+}
+
+func (rep *AuctionRep) bidDone() {
+	atomic.AddInt32(&rep.inFlightBids, -1)
+}
+
+// private internals -- no locks here
+// the number of reservations the rep may still take on, -1 if there is no limit
+func (rep *AuctionRep) reservationsAdmitted() (int, error) {
+	if rep.limits.MaxReservations <= 0 {
+		return -1, nil
+	}
+
+	reservations, err := rep.delegate.Reservations()
+	if err != nil {
+		return 0, err
+	}
+
+	admitted := rep.limits.MaxReservations - len(reservations)
+	if admitted < 0 {
+		admitted = 0
+	}
+	return admitted, nil
+}
+
+// private internals -- no locks here
+func (rep *AuctionRep) admitReservation() error {
+	admitted, err := rep.reservationsAdmitted()
+	if err != nil {
+		return err
+	}
+
+	if admitted == 0 {
+		repBusy.Inc(rep.repGuid, "reservations")
+		return auctiontypes.RepBusy
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package auctionrep
+
+import "github.com/cloudfoundry-incubator/auction/metrics"
+
+var (
+	reservationsHeld    = metrics.Default.Gauge("auction_rep_reservations_held", "Reservations the rep is holding for auctioneers.", "rep")
+	reservationsExpired = metrics.Default.Counter("auction_rep_reservations_expired_total", "Reservations released because they were never run.", "rep")
+	repBusy             = metrics.Default.Counter("auction_rep_busy_total", "Bids and reservations the rep refused for being at one of its limits.", "rep", "limit")
+	repDraining         = metrics.Default.Gauge("auction_rep_draining", "1 while the rep is draining and refuses new instances, else 0.", "rep")
+)
@@ -0,0 +1,81 @@
+package auctionrep
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// A StopBidScorer turns the state of a rep running instances of a process index into its stop bid:
+// how good a place the rep is to keep one of them while every other instance of the index is stopped.
+// Lower bids keep their instance; the rep keeps the first of its InstanceGuids, the oldest if it knows their start times.
+type StopBidScorer interface {
+	ScoreStop(repStopIndexScoreInfo StopIndexScoreInfo) float64
+}
+
+// LeastLoadedStopScorer bids what Scorer would make of the rep once it had stopped all but one of its instances of the index,
+// so the instance is kept on the least loaded rep
+type LeastLoadedStopScorer struct {
+	Scorer BidScorer
+}
+
+func (s LeastLoadedStopScorer) ScoreStop(repStopIndexScoreInfo StopIndexScoreInfo) float64 {
+	instanceScoreInfo := repStopIndexScoreInfo.InstanceScoreInfo
+	instanceScoreInfo.NumInstancesForProcessGuid -= len(repStopIndexScoreInfo.InstanceGuidsForProcessIndex) - 1
+	return s.Scorer.Score(instanceScoreInfo)
+}
+
+// OldestInstanceStopScorer bids the lower the longer the rep's oldest instance of the index has been running,
+// so the oldest instance is kept; reps that do not know when their instances started bid 0
+type OldestInstanceStopScorer struct{}
+
+func (s OldestInstanceStopScorer) ScoreStop(repStopIndexScoreInfo StopIndexScoreInfo) float64 {
+	oldest := time.Time{}
+	for _, startedAt := range repStopIndexScoreInfo.InstanceStartTimes {
+		if oldest.IsZero() || startedAt.Before(oldest) {
+			oldest = startedAt
+		}
+	}
+
+	if oldest.IsZero() {
+		return 0
+	}
+	return -time.Since(oldest).Seconds()
+}
+
+// DefaultStopScorer keeps the instance on the rep the DefaultScorer finds least loaded
+var DefaultStopScorer StopBidScorer = LeastLoadedStopScorer{Scorer: DefaultScorer}
+
+// the built-in stop scorers, by the name reps are configured with
+var stopBidScorers = map[string]StopBidScorer{
+	"least_loaded":    DefaultStopScorer,
+	"oldest_instance": OldestInstanceStopScorer{},
+}
+
+type UnknownStopBidScorerError struct {
+	StopBidScorer string
+}
+
+func (e UnknownStopBidScorerError) Error() string {
+	return fmt.Sprintf("unknown stop bid scorer %q", e.StopBidScorer)
+}
+
+// StopBidScorers returns the sorted names of the built-in stop scorers.
+func StopBidScorers() []string {
+	names := []string{}
+	for name := range stopBidScorers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func LookupStopBidScorer(name string) (StopBidScorer, error) {
+	scorer, ok := stopBidScorers[name]
+	if !ok {
+		return nil, UnknownStopBidScorerError{StopBidScorer: name}
+	}
+
+	return scorer, nil
+}
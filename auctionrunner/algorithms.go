@@ -0,0 +1,156 @@
+package auctionrunner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+/*
+
+A StartAuctionAlgorithm places a single instance among the reps in the request.
+It returns the winning rep (or "" if there is none), the number of rounds
+and the number of communications it took.
+
+Once ctx is done it must not start another round, and it must release any
+reservation it is holding instead of running the instance.
+
+*/
+
+type StartAuctionAlgorithm func(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int)
+
+/*
+
+A DryRunStartAuctionAlgorithm answers where its StartAuctionAlgorithm would
+place the instance without changing any rep: all it can do is ask for bids.
+It returns the would-be winner (or ""), the bids of the deciding round ranked
+best first, and the number of rounds and communications it took.
+
+*/
+
+type DryRunStartAuctionAlgorithm func(ctx context.Context, bidder StartAuctionBidder, auctionRequest auctiontypes.StartAuctionRequest) (string, auctiontypes.StartAuctionBids, int, int)
+
+type StartAuctionBidder interface {
+	BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids
+}
+
+type UnknownAlgorithmError struct {
+	Algorithm string
+}
+
+func (e UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("unknown algorithm %q", e.Algorithm)
+}
+
+type DryRunNotSupportedError struct {
+	Algorithm string
+}
+
+func (e DryRunNotSupportedError) Error() string {
+	return fmt.Sprintf("algorithm %q does not support dry runs", e.Algorithm)
+}
+
+var algorithmsLock = &sync.RWMutex{}
+var algorithms = map[string]StartAuctionAlgorithm{}
+var dryRunAlgorithms = map[string]DryRunStartAuctionAlgorithm{}
+
+func init() {
+	RegisterStartAuctionAlgorithm("all_rebid", allRebidAuction)
+	RegisterStartAuctionAlgorithm("all_reserve", allReserveAuction)
+	RegisterStartAuctionAlgorithm("pick_among_best", pickAmongBestAuction)
+	RegisterStartAuctionAlgorithm("pick_best", pickBestAuction)
+	RegisterStartAuctionAlgorithm("pick_two", pickTwoAuction)
+	RegisterStartAuctionAlgorithm("reserve_n_best", reserveNBestAuction)
+	RegisterStartAuctionAlgorithm("random", randomAuction)
+
+	RegisterDryRunStartAuctionAlgorithm("all_rebid", dryRunBestBid)
+	RegisterDryRunStartAuctionAlgorithm("all_reserve", dryRunBestBid)
+	RegisterDryRunStartAuctionAlgorithm("pick_among_best", dryRunPickAmongBest)
+	RegisterDryRunStartAuctionAlgorithm("pick_best", dryRunBestBid)
+	RegisterDryRunStartAuctionAlgorithm("pick_two", dryRunPickTwo)
+	RegisterDryRunStartAuctionAlgorithm("reserve_n_best", dryRunBestBid)
+	//random picks without asking for bids, so there is nothing to dry-run
+}
+
+// RegisterStartAuctionAlgorithm makes an algorithm available under name to
+// StartAuctionRules.Algorithm. It panics if name is already registered.
+func RegisterStartAuctionAlgorithm(name string, algorithm StartAuctionAlgorithm) {
+	algorithmsLock.Lock()
+	defer algorithmsLock.Unlock()
+
+	if algorithm == nil {
+		panic("auctionrunner: nil algorithm " + name)
+	}
+
+	if _, exists := algorithms[name]; exists {
+		panic("auctionrunner: algorithm registered twice " + name)
+	}
+
+	algorithms[name] = algorithm
+}
+
+// RegisterDryRunStartAuctionAlgorithm declares that the algorithm registered under
+// name supports dry runs. It panics if name is not registered or already has one.
+func RegisterDryRunStartAuctionAlgorithm(name string, dryRunAlgorithm DryRunStartAuctionAlgorithm) {
+	algorithmsLock.Lock()
+	defer algorithmsLock.Unlock()
+
+	if dryRunAlgorithm == nil {
+		panic("auctionrunner: nil dry-run algorithm " + name)
+	}
+
+	if _, exists := algorithms[name]; !exists {
+		panic("auctionrunner: dry-run for unknown algorithm " + name)
+	}
+
+	if _, exists := dryRunAlgorithms[name]; exists {
+		panic("auctionrunner: dry-run algorithm registered twice " + name)
+	}
+
+	dryRunAlgorithms[name] = dryRunAlgorithm
+}
+
+// StartAuctionAlgorithms returns the sorted names of the registered algorithms.
+func StartAuctionAlgorithms() []string {
+	algorithmsLock.RLock()
+	defer algorithmsLock.RUnlock()
+
+	names := []string{}
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookupStartAuctionAlgorithm(name string) (StartAuctionAlgorithm, error) {
+	algorithmsLock.RLock()
+	defer algorithmsLock.RUnlock()
+
+	algorithm, ok := algorithms[name]
+	if !ok {
+		return nil, UnknownAlgorithmError{Algorithm: name}
+	}
+
+	return algorithm, nil
+}
+
+func lookupDryRunStartAuctionAlgorithm(name string) (DryRunStartAuctionAlgorithm, error) {
+	algorithmsLock.RLock()
+	defer algorithmsLock.RUnlock()
+
+	if _, ok := algorithms[name]; !ok {
+		return nil, UnknownAlgorithmError{Algorithm: name}
+	}
+
+	dryRunAlgorithm, ok := dryRunAlgorithms[name]
+	if !ok {
+		return nil, DryRunNotSupportedError{Algorithm: name}
+	}
+
+	return dryRunAlgorithm, nil
+}
@@ -1,6 +1,10 @@
 package auctionrunner
 
-import "github.com/cloudfoundry-incubator/auction/auctiontypes"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
 
 /*
 
@@ -11,11 +15,13 @@ Get the bids from the subset of reps
 
 */
 
-func allRebidAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
+func allRebidAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
 	rounds, numCommunications := 1, 0
-	auctionInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(auctionRequest.LRPStartAuction)
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		startRound(ctx)
 
-	for ; rounds <= auctionRequest.Rules.MaxRounds; rounds++ {
 		//pick a subset
 		firstRoundReps := auctionRequest.RepGuids.RandomSubsetByFraction(auctionRequest.Rules.MaxBiddingPoolFraction, auctionRequest.Rules.MinBiddingPool)
 
@@ -23,10 +29,13 @@ func allRebidAuction(client auctiontypes.RepPoolClient, auctionRequest auctionty
 		numCommunications += len(firstRoundReps)
 		firstRoundScores := client.BidForStartAuction(firstRoundReps, auctionInfo)
 		if firstRoundScores.AllFailed() {
+			abandonRound(ctx, abandonedNoBids)
 			continue
 		}
 
-		winner := firstRoundScores.FilterErrors().Shuffle().Sort()[0]
+		//prefer the zone running the fewest instances of the process
+		zones := firstRoundScores.InstancesByZone()
+		winner := firstRoundScores.FilterErrors().Shuffle().SortBalancingZones(zones)[0]
 
 		// tell the winner to reserve
 		numCommunications += 1
@@ -39,22 +48,36 @@ func allRebidAuction(client auctiontypes.RepPoolClient, auctionRequest auctionty
 
 		//if the winner ran out of space: bail
 		if winnerRecast.Error != "" {
+			abandonRound(ctx, abandonedNoReservations)
 			continue
 		}
 
 		// if the second place winner has a better bid than the original winner: bail
 		if !secondRoundScores.AllFailed() {
-			secondPlace := secondRoundScores.FilterErrors().Shuffle().Sort()[0]
-			if secondPlace.Bid < winnerRecast.Bid {
+			secondPlace := secondRoundScores.FilterErrors().Shuffle().SortBalancingZones(zones)[0]
+			if zones.Less(secondPlace, winnerRecast) {
 				client.ReleaseReservation([]string{winner.Rep}, auctionInfo)
 				numCommunications += 1
+				abandonRound(ctx, abandonedOutbid)
 				continue
 			}
 		}
 
-		client.Run(winner.Rep, auctionRequest.LRPStartAuction)
-		numCommunications += 1
-		return winner.Rep, rounds, numCommunications
+		//if the auction is out of time: release and bail
+		if ctx.Err() != nil {
+			client.ReleaseReservation([]string{winner.Rep}, auctionInfo)
+			numCommunications += 1
+			abandonRound(ctx, abandonedOutOfTime)
+			break
+		}
+
+		ran, n := runReserved(client, []string{winner.Rep}, auctionInfo, auctionRequest.LRPStartAuction)
+		numCommunications += n
+		if ran == "" {
+			abandonRound(ctx, abandonedRunFailed)
+			continue
+		}
+		return ran, rounds, numCommunications
 	}
 
 	return "", rounds, numCommunications
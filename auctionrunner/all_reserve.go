@@ -1,6 +1,10 @@
 package auctionrunner
 
-import "github.com/cloudfoundry-incubator/auction/auctiontypes"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
 
 /*
 
@@ -9,11 +13,13 @@ Tell the subset of reps to reserve
         Tell the winner to run and the others to release
 
 */
-func allReserveAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
+func allReserveAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
 	rounds, numCommunications := 1, 0
-	auctionInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(auctionRequest.LRPStartAuction)
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		startRound(ctx)
 
-	for ; rounds <= auctionRequest.Rules.MaxRounds; rounds++ {
 		//pick a subset
 		firstRoundReps := auctionRequest.RepGuids.RandomSubsetByFraction(auctionRequest.Rules.MaxBiddingPoolFraction, auctionRequest.Rules.MinBiddingPool)
 
@@ -22,18 +28,29 @@ func allReserveAuction(client auctiontypes.RepPoolClient, auctionRequest auction
 		bids := client.RebidThenTentativelyReserve(firstRoundReps, auctionInfo)
 
 		if bids.AllFailed() {
+			abandonRound(ctx, abandonedNoReservations)
 			continue
 		}
 
-		orderedReps := bids.FilterErrors().Shuffle().Sort().Reps()
+		orderedReps := bids.FilterErrors().Shuffle().SortBalancingZones(bids.InstancesByZone()).Reps()
 
-		numCommunications += len(orderedReps)
-		client.Run(orderedReps[0], auctionRequest.LRPStartAuction)
-		if len(orderedReps) > 1 {
-			client.ReleaseReservation(orderedReps[1:], auctionInfo)
+		//if the auction is out of time: release everyone and bail
+		if ctx.Err() != nil {
+			numCommunications += len(orderedReps)
+			client.ReleaseReservation(orderedReps, auctionInfo)
+			abandonRound(ctx, abandonedOutOfTime)
+			break
+		}
+
+		//run on the best, falling back to the next best if it fails
+		ran, n := runReserved(client, orderedReps, auctionInfo, auctionRequest.LRPStartAuction)
+		numCommunications += n
+		if ran == "" {
+			abandonRound(ctx, abandonedRunFailed)
+			continue
 		}
 
-		return orderedReps[0], rounds, numCommunications
+		return ran, rounds, numCommunications
 	}
 
 	return "", rounds, numCommunications
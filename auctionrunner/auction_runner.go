@@ -1,10 +1,12 @@
 package auctionrunner
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/auction/util"
 )
 
 var AllBiddersFull = errors.New("all the bidders were full")
@@ -14,43 +16,161 @@ var DefaultStartAuctionRules = auctiontypes.StartAuctionRules{
 	MaxRounds:              40,
 	MaxBiddingPoolFraction: 0.2,
 	MinBiddingPool:         10,
+	NumChoices:             defaultNumChoices,
 }
 
 type auctionRunner struct {
-	client auctiontypes.RepPoolClient
+	client    auctiontypes.RepPoolClient
+	observers []Observer
+	inFlight  *inFlight
 }
 
-func New(client auctiontypes.RepPoolClient) *auctionRunner {
+func New(client auctiontypes.RepPoolClient, observers ...Observer) *auctionRunner {
 	return &auctionRunner{
-		client: client,
+		client:    client,
+		observers: observers,
+		inFlight:  newInFlight(),
 	}
 }
 
 func (a *auctionRunner) RunLRPStartAuction(auctionRequest auctiontypes.StartAuctionRequest) (auctiontypes.StartAuctionResult, error) {
-	result := auctiontypes.StartAuctionResult{
+	return a.RunLRPStartAuctionWithContext(context.Background(), auctionRequest)
+}
+
+func (a *auctionRunner) RunLRPStartAuctionWithContext(ctx context.Context, auctionRequest auctiontypes.StartAuctionRequest) (result auctiontypes.StartAuctionResult, err error) {
+	result = auctiontypes.StartAuctionResult{
 		LRPStartAuction: auctionRequest.LRPStartAuction,
+		TraceID:         util.RandomGuid(),
+	}
+
+	auctionRequest.Rules, err = withStrategy(auctionRequest.Rules)
+	if err != nil {
+		return result, err
+	}
+
+	if auctionRequest.DryRun {
+		return a.dryRunLRPStartAuction(ctx, result, auctionRequest)
+	}
+
+	algorithm, err := lookupStartAuctionAlgorithm(auctionRequest.Rules.Algorithm)
+	if err != nil {
+		return result, err
+	}
+
+	//a duplicate is turned away before it counts as an auction: it is neither started, failed nor observed
+	instanceGuid := auctionRequest.LRPStartAuction.InstanceGuid
+	if !a.inFlight.claim(instanceGuid)[0] {
+		startAuctionsDuplicated.Inc(auctionRequest.Rules.Algorithm)
+		return result, auctiontypes.AuctionInProgress
+	}
+	defer a.inFlight.release(instanceGuid)
+
+	startAuctionsStarted.Inc(auctionRequest.Rules.Algorithm)
+	defer func() {
+		recordStartAuction(auctionRequest.Rules.Algorithm, result, err)
+	}()
+
+	ctx, cancel := withMaxDuration(ctx, auctionRequest.Rules)
+	defer cancel()
+
+	constraintFailures := newConstraintFailureClient(tracedClient(a.client, result.TraceID))
+	runFailures := newRunFailureClient(constraintFailures)
+	var client auctiontypes.RepPoolClient = runFailures
+
+	var explanations *explanationClient
+	if auctionRequest.Rules.ExplainBids {
+		explanations = newExplanationClient(client)
+		client = explanations
+	}
+
+	var h *history
+	observerList := a.observers
+	if auctionRequest.Rules.RecordHistory {
+		h = newHistory()
+		observerList = append(observerList[:len(observerList):len(observerList)], h)
+	}
+
+	if len(observerList) > 0 {
+		var o *observers
+		ctx, o = withObservers(ctx, auctionRequest.LRPStartAuction, observerList)
+		client = newObserverClient(client, o)
+		defer func() {
+			o.auctionFinished(result, err)
+		}()
+	}
+
+	//deferred last so observers are told of the finished auction with its history
+	if h != nil {
+		defer func() {
+			result.History = h.Rounds()
+		}()
 	}
 
 	t := time.Now()
-	switch auctionRequest.Rules.Algorithm {
-	case "all_rebid":
-		result.Winner, result.NumRounds, result.NumCommunications = allRebidAuction(a.client, auctionRequest)
-	case "all_reserve":
-		result.Winner, result.NumRounds, result.NumCommunications = allReserveAuction(a.client, auctionRequest)
-	case "pick_among_best":
-		result.Winner, result.NumRounds, result.NumCommunications = pickAmongBestAuction(a.client, auctionRequest)
-	case "pick_best":
-		result.Winner, result.NumRounds, result.NumCommunications = pickBestAuction(a.client, auctionRequest)
-	case "reserve_n_best":
-		result.Winner, result.NumRounds, result.NumCommunications = reserveNBestAuction(a.client, auctionRequest)
-	case "random":
-		result.Winner, result.NumRounds, result.NumCommunications = randomAuction(a.client, auctionRequest)
-	default:
-		panic("unkown algorithm " + auctionRequest.Rules.Algorithm)
+	result.Winner, result.NumRounds, result.NumCommunications = algorithm(ctx, client, auctionRequest)
+
+	//nobody has room: make some by evicting lower-priority instances (pointless if the reps are only busy, draining or may not run it anyway)
+	if result.Winner == "" && ctx.Err() == nil && auctionRequest.Rules.Preemption && !constraintFailures.onlyConstraintFailures() && constraintFailures.softFailure() == nil {
+		var rounds, numCommunications int
+		result.Winner, result.Evicted, rounds, numCommunications = preemptionAuction(ctx, client, auctionRequest)
+		result.NumRounds += rounds
+		result.NumCommunications += numCommunications
 	}
+
 	result.BiddingDuration = time.Since(t)
+	result.RunFailures = runFailures.runFailures()
+	if explanations != nil && result.Winner != "" {
+		result.Explanation = explanations.explanationFor(result.Winner)
+	}
 
 	if result.Winner == "" {
+		if ctx.Err() != nil {
+			return result, contextError(ctx)
+		}
+		if constraintFailures.onlyConstraintFailures() {
+			return result, auctiontypes.ConstraintNotSatisfied
+		}
+		if softFailure := constraintFailures.softFailure(); softFailure != nil {
+			return result, softFailure
+		}
+		return result, auctiontypes.InsufficientResources
+	}
+
+	return result, nil
+}
+
+func (a *auctionRunner) dryRunLRPStartAuction(ctx context.Context, result auctiontypes.StartAuctionResult, auctionRequest auctiontypes.StartAuctionRequest) (auctiontypes.StartAuctionResult, error) {
+	dryRunAlgorithm, err := lookupDryRunStartAuctionAlgorithm(auctionRequest.Rules.Algorithm)
+	if err != nil {
+		return result, err
+	}
+
+	ctx, cancel := withMaxDuration(ctx, auctionRequest.Rules)
+	defer cancel()
+
+	client := newConstraintFailureClient(tracedClient(a.client, result.TraceID))
+
+	t := time.Now()
+	result.Winner, result.RankedBids, result.NumRounds, result.NumCommunications = dryRunAlgorithm(ctx, client, auctionRequest)
+	result.BiddingDuration = time.Since(t)
+
+	for _, bid := range result.RankedBids {
+		if bid.Rep == result.Winner && result.Winner != "" {
+			result.Explanation = bid.Explanation
+			break
+		}
+	}
+
+	if result.Winner == "" {
+		if ctx.Err() != nil {
+			return result, contextError(ctx)
+		}
+		if client.onlyConstraintFailures() {
+			return result, auctiontypes.ConstraintNotSatisfied
+		}
+		if softFailure := client.softFailure(); softFailure != nil {
+			return result, softFailure
+		}
 		return result, auctiontypes.InsufficientResources
 	}
 
@@ -58,14 +178,144 @@ func (a *auctionRunner) RunLRPStartAuction(auctionRequest auctiontypes.StartAuct
 }
 
 func (a *auctionRunner) RunLRPStopAuction(auctionRequest auctiontypes.StopAuctionRequest) (auctiontypes.StopAuctionResult, error) {
+	return a.RunLRPStopAuctionWithContext(context.Background(), auctionRequest)
+}
+
+func (a *auctionRunner) RunLRPStopAuctionWithContext(ctx context.Context, auctionRequest auctiontypes.StopAuctionRequest) (auctiontypes.StopAuctionResult, error) {
 	result := auctiontypes.StopAuctionResult{
 		LRPStopAuction: auctionRequest.LRPStopAuction,
 	}
 
-	var err error
+	policy, err := lookupStopPolicy(auctionRequest.Policy)
+	if err != nil {
+		return result, err
+	}
+
 	t := time.Now()
-	result.Winner, result.NumCommunications, err = stopAuction(a.client, auctionRequest)
+	result.Winner, result.StopFailures, result.NumCommunications, err = stopAuction(ctx, a.client, policy, auctionRequest)
 	result.BiddingDuration = time.Since(t)
 
 	return result, err
 }
+
+func (a *auctionRunner) RunLRPStartAuctionBatch(auctionRequest auctiontypes.StartAuctionBatchRequest) ([]auctiontypes.StartAuctionResult, error) {
+	return a.RunLRPStartAuctionBatchWithContext(context.Background(), auctionRequest)
+}
+
+func (a *auctionRunner) RunLRPStartAuctionBatchWithContext(ctx context.Context, auctionRequest auctiontypes.StartAuctionBatchRequest) ([]auctiontypes.StartAuctionResult, error) {
+	var err error
+	auctionRequest.Rules, err = withBatchRules(auctionRequest.Rules)
+	if err != nil {
+		results := make([]auctiontypes.StartAuctionResult, len(auctionRequest.LRPStartAuctions))
+		for i, lrpStartAuction := range auctionRequest.LRPStartAuctions {
+			results[i].LRPStartAuction = lrpStartAuction
+		}
+		return results, err
+	}
+
+	ctx, cancel := withMaxDuration(ctx, auctionRequest.Rules)
+	defer cancel()
+
+	//only auction the instances nobody else is auctioning
+	lrpStartAuctions := auctionRequest.LRPStartAuctions
+	instanceGuids := make([]string, len(lrpStartAuctions))
+	for i, lrpStartAuction := range lrpStartAuctions {
+		instanceGuids[i] = lrpStartAuction.InstanceGuid
+	}
+
+	claimed := a.inFlight.claim(instanceGuids...)
+	auctionRequest.LRPStartAuctions = nil
+	for i, lrpStartAuction := range lrpStartAuctions {
+		if claimed[i] {
+			auctionRequest.LRPStartAuctions = append(auctionRequest.LRPStartAuctions, lrpStartAuction)
+			defer a.inFlight.release(lrpStartAuction.InstanceGuid)
+		}
+	}
+
+	//the whole batch is one auction as far as tracing is concerned
+	traceID := util.RandomGuid()
+
+	t := time.Now()
+	claimedResults, claimedErrs := batchStartAuction(ctx, tracedClient(a.client, traceID), auctionRequest)
+	biddingDuration := time.Since(t)
+
+	results := make([]auctiontypes.StartAuctionResult, len(lrpStartAuctions))
+	errs := make([]error, len(lrpStartAuctions))
+	for i, lrpStartAuction := range lrpStartAuctions {
+		if !claimed[i] {
+			results[i].LRPStartAuction = lrpStartAuction
+			errs[i] = auctiontypes.AuctionInProgress
+			continue
+		}
+		results[i], claimedResults = claimedResults[0], claimedResults[1:]
+		errs[i], claimedErrs = claimedErrs[0], claimedErrs[1:]
+	}
+
+	//every instance carries its own error; the batch reports the one that matters most
+	for i := range results {
+		results[i].TraceID = traceID
+		results[i].BiddingDuration = biddingDuration
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+		}
+		if batchErrorRank(errs[i]) > batchErrorRank(err) {
+			err = errs[i]
+		}
+	}
+
+	for i := range results {
+		if !claimed[i] {
+			startAuctionsDuplicated.Inc(batchAlgorithm)
+			continue
+		}
+
+		startAuctionsStarted.Inc(batchAlgorithm)
+		recordStartAuction(batchAlgorithm, results[i], errs[i])
+		for _, observer := range a.observers {
+			observer.AuctionFinished(results[i], errs[i])
+		}
+	}
+
+	return results, err
+}
+
+// the instances that were auctioned say how the batch fared before its duplicates do
+func batchErrorRank(err error) int {
+	switch err {
+	case nil:
+		return 0
+	case auctiontypes.AuctionInProgress:
+		return 1
+	case auctiontypes.InsufficientResources:
+		return 2
+	case auctiontypes.ConstraintNotSatisfied:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func withMaxDuration(ctx context.Context, rules auctiontypes.StartAuctionRules) (context.Context, context.CancelFunc) {
+	if rules.MaxDuration <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, rules.MaxDuration)
+}
+
+func contextError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return auctiontypes.AuctionDeadlineExceeded
+	}
+
+	return auctiontypes.AuctionCancelled
+}
+
+// every request sent on behalf of the auction carries its trace ID, if the client can tag them
+func tracedClient(client auctiontypes.RepPoolClient, traceID string) auctiontypes.RepPoolClient {
+	tracingClient, ok := client.(auctiontypes.TracingRepPoolClient)
+	if !ok {
+		return client
+	}
+	return tracingClient.WithTraceID(traceID)
+}
@@ -0,0 +1,319 @@
+package auctionrunner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctionrep"
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/runtime-schema/models"
+)
+
+/*
+
+Get one snapshot from every rep
+	Place the whole batch against the snapshots, largest instances first
+	(a rep bids the score it gave the instance, moved by what was already placed on it)
+		Tell each rep to reserve everything placed on it, in one message
+			Run the instances that were reserved, try the rest again next round
+			(unless no rep may run them at all: those are dropped)
+			Reps that fail to run an instance sit out the rounds that follow
+Every instance left unplaced gets its own error: unsatisfiable, out of time or out of room
+
+*/
+
+type BatchRuleNotSupportedError struct {
+	Rule string
+}
+
+func (e BatchRuleNotSupportedError) Error() string {
+	return fmt.Sprintf("batch auctions do not support %s", e.Rule)
+}
+
+// batches neither preempt, record history nor explain bids: rather than ignore rules asking for those, refuse them
+func withBatchRules(rules auctiontypes.StartAuctionRules) (auctiontypes.StartAuctionRules, error) {
+	switch {
+	case rules.Preemption:
+		return rules, BatchRuleNotSupportedError{Rule: "Preemption"}
+	case rules.RecordHistory:
+		return rules, BatchRuleNotSupportedError{Rule: "RecordHistory"}
+	case rules.ExplainBids:
+		return rules, BatchRuleNotSupportedError{Rule: "ExplainBids"}
+	}
+
+	return withStrategy(rules)
+}
+
+func batchStartAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionBatchRequest) ([]auctiontypes.StartAuctionResult, []error) {
+	numCommunications := 0
+	results := make([]auctiontypes.StartAuctionResult, len(auctionRequest.LRPStartAuctions))
+	errs := make([]error, len(auctionRequest.LRPStartAuctions))
+	pending := []int{}
+	for i, lrpStartAuction := range auctionRequest.LRPStartAuctions {
+		results[i].LRPStartAuction = lrpStartAuction
+		pending = append(pending, i)
+	}
+
+	sort.Stable(byMemoryDescending{auctionRequest: auctionRequest, indices: pending})
+
+	for rounds := 1; rounds <= auctionRequest.Rules.MaxRounds && len(pending) > 0 && ctx.Err() == nil; rounds++ {
+		auctionInfos := make([]auctiontypes.StartAuctionInfo, len(pending))
+		for i, index := range pending {
+			lrpStartAuction := auctionRequest.LRPStartAuctions[index]
+			auctionInfos[i] = auctiontypes.NewStartAuctionInfoFromLRPStartAuction(lrpStartAuction)
+			auctionInfos[i].RequiredAttributes = append(auctionInfos[i].RequiredAttributes, auctionRequest.RequiredAttributesByProcessGuid[lrpStartAuction.ProcessGuid]...)
+			auctionInfos[i].AffinityRules = auctionRequest.AffinityRulesByProcessGuid[lrpStartAuction.ProcessGuid]
+			auctionInfos[i].Strategy = auctionRequest.Rules.Strategy
+			auctionInfos[i].Resources = auctionRequest.ResourcesByProcessGuid[lrpStartAuction.ProcessGuid]
+			results[index].NumRounds = rounds
+		}
+
+		//get everyone's snapshot, if they're all failing: bail
+		numCommunications += len(auctionRequest.RepGuids)
+		bids := client.BidForBatchStartAuction(auctionRequest.RepGuids, auctionInfos).FilterErrors().Shuffle()
+		if len(bids) == 0 {
+			continue
+		}
+
+		//place everything against the snapshots, nobody may run the unsatisfiable ones: stop trying
+		placements, unsatisfiable := placeBatch(bids, auctionInfos)
+		for _, index := range pending {
+			if unsatisfiable[auctionRequest.LRPStartAuctions[index].InstanceGuid] {
+				errs[index] = auctiontypes.ConstraintNotSatisfied
+			}
+		}
+		pending = withoutInstances(auctionRequest, pending, unsatisfiable)
+
+		if len(placements) == 0 {
+			continue
+		}
+
+		//ask each rep to reserve what was placed on it
+		numCommunications += len(placements)
+		reservations := client.TentativelyReserveBatch(placements).FilterErrors()
+
+		reserved := map[string]string{}
+		for _, reservation := range reservations {
+			reserved[reservation.InstanceGuid] = reservation.Rep
+		}
+
+		//if the batch is out of time: release everything that was reserved and bail
+		if ctx.Err() != nil {
+			numCommunications += len(reservations)
+			releaseBatch(client, placements, reserved)
+			break
+		}
+
+		stillPending := []int{}
+		runErrs := make([]error, len(auctionRequest.LRPStartAuctions))
+		wg := &sync.WaitGroup{}
+		for _, index := range pending {
+			lrpStartAuction := auctionRequest.LRPStartAuctions[index]
+			repGuid, ok := reserved[lrpStartAuction.InstanceGuid]
+			if !ok {
+				stillPending = append(stillPending, index)
+				continue
+			}
+
+			numCommunications += 1
+			wg.Add(1)
+			go func(index int, repGuid string, lrpStartAuction models.LRPStartAuction) {
+				runErrs[index] = client.Run(repGuid, lrpStartAuction)
+				wg.Done()
+			}(index, repGuid, lrpStartAuction)
+		}
+		wg.Wait()
+
+		//free the room held by reps that failed to run and try those instances again elsewhere
+		for i, index := range pending {
+			repGuid, ok := reserved[auctionRequest.LRPStartAuctions[index].InstanceGuid]
+			if !ok {
+				continue
+			}
+
+			if runErrs[index] != nil {
+				numCommunications += 1
+				client.ReleaseReservation([]string{repGuid}, auctionInfos[i])
+				results[index].RunFailures = append(results[index].RunFailures, auctiontypes.RepFailure{
+					Rep:          repGuid,
+					InstanceGuid: auctionInfos[i].InstanceGuid,
+					Error:        runErrs[index].Error(),
+				})
+				stillPending = append(stillPending, index)
+				auctionRequest.RepGuids = auctionRequest.RepGuids.Without(repGuid)
+				continue
+			}
+
+			results[index].Winner = repGuid
+		}
+		pending = stillPending
+	}
+
+	for _, index := range pending {
+		if ctx.Err() != nil {
+			errs[index] = contextError(ctx)
+		} else {
+			errs[index] = auctiontypes.InsufficientResources
+		}
+	}
+
+	distributeCommunications(results, numCommunications)
+
+	return results, errs
+}
+
+func placeBatch(bids auctiontypes.BatchStartAuctionBids, auctionInfos []auctiontypes.StartAuctionInfo) (map[string][]auctiontypes.StartAuctionInfo, map[string]bool) {
+	initialSnapshots := make([]auctionrep.StartInstanceScoreInfo, len(bids))
+	snapshots := make([]auctionrep.StartInstanceScoreInfo, len(bids))
+	nInstances := make([]map[string]int, len(bids))
+	zonesByProcessGuid := map[string]auctiontypes.InstancesByZone{}
+	for i, bid := range bids {
+		snapshots[i] = auctionrep.StartInstanceScoreInfo{
+			RemainingResources: bid.RemainingResources,
+			TotalResources:     bid.TotalResources,
+			Zone:               bid.Zone,
+			Attributes:         bid.Attributes,
+			ProcessGuids:       append([]string{}, bid.ProcessGuids...),
+		}
+		initialSnapshots[i] = snapshots[i]
+		nInstances[i] = map[string]int{}
+		for processGuid, n := range bid.NumInstancesForProcessGuids {
+			nInstances[i][processGuid] = n
+			if bid.Zone == "" {
+				continue
+			}
+			if zonesByProcessGuid[processGuid] == nil {
+				zonesByProcessGuid[processGuid] = auctiontypes.InstancesByZone{}
+			}
+			zonesByProcessGuid[processGuid][bid.Zone] += n
+		}
+	}
+
+	placements := map[string][]auctiontypes.StartAuctionInfo{}
+	unsatisfiable := map[string]bool{}
+	for _, auctionInfo := range auctionInfos {
+		if zonesByProcessGuid[auctionInfo.ProcessGuid] == nil {
+			zonesByProcessGuid[auctionInfo.ProcessGuid] = auctiontypes.InstancesByZone{}
+		}
+		zones := zonesByProcessGuid[auctionInfo.ProcessGuid]
+
+		winner := -1
+		lowestBid := 0.0
+		numConstraintFailures := 0
+		for i := range snapshots {
+			snapshots[i].NumInstancesForProcessGuid = nInstances[i][auctionInfo.ProcessGuid]
+			err := auctionrep.SatisfiesConstraints(auctionInfo, snapshots[i])
+			if err == auctiontypes.ConstraintNotSatisfied {
+				numConstraintFailures++
+			}
+			if err != nil {
+				continue
+			}
+
+			bid := batchBid(bids[i], auctionInfo, initialSnapshots[i], snapshots[i]) + auctionrep.AffinityPenalty(auctionInfo, snapshots[i])
+			if winner == -1 || lessZoneBalanced(zones, snapshots[i].Zone, bid, snapshots[winner].Zone, lowestBid) {
+				winner, lowestBid = i, bid
+			}
+		}
+
+		if winner == -1 {
+			if numConstraintFailures == len(snapshots) {
+				unsatisfiable[auctionInfo.InstanceGuid] = true
+			}
+			continue
+		}
+
+		snapshots[winner].RemainingResources = snapshots[winner].RemainingResources.Subtract(auctionInfo.Demand())
+		if nInstances[winner][auctionInfo.ProcessGuid] == 0 {
+			snapshots[winner].ProcessGuids = append(snapshots[winner].ProcessGuids, auctionInfo.ProcessGuid)
+		}
+		nInstances[winner][auctionInfo.ProcessGuid] += 1
+		if snapshots[winner].Zone != "" {
+			zones[snapshots[winner].Zone] += 1
+		}
+
+		repGuid := bids[winner].Rep
+		placements[repGuid] = append(placements[repGuid], auctionInfo)
+	}
+
+	return placements, unsatisfiable
+}
+
+// batchBid is the rep's own score for the instance, moved by as much as the instances placed on the rep since its snapshot
+// move the DefaultScorer's: the auctioneer cannot ask the rep again, and has only the DefaultScorer to go by.
+// Reps that do not score their snapshots are scored with the DefaultScorer throughout.
+func batchBid(bid auctiontypes.BatchStartAuctionBid, auctionInfo auctiontypes.StartAuctionInfo, initialSnapshot auctionrep.StartInstanceScoreInfo, snapshot auctionrep.StartInstanceScoreInfo) float64 {
+	current := auctionrep.StartAuctionBid(auctionInfo, snapshot)
+
+	score, ok := bid.Scores[auctionInfo.InstanceGuid]
+	if !ok {
+		return current
+	}
+
+	initialSnapshot.NumInstancesForProcessGuid = bid.NumInstancesForProcessGuids[auctionInfo.ProcessGuid]
+	return score + current - auctionrep.StartAuctionBid(auctionInfo, initialSnapshot)
+}
+
+func withoutInstances(auctionRequest auctiontypes.StartAuctionBatchRequest, pending []int, instanceGuids map[string]bool) []int {
+	remaining := []int{}
+	for _, index := range pending {
+		if !instanceGuids[auctionRequest.LRPStartAuctions[index].InstanceGuid] {
+			remaining = append(remaining, index)
+		}
+	}
+
+	return remaining
+}
+
+func lessZoneBalanced(zones auctiontypes.InstancesByZone, zoneA string, bidA float64, zoneB string, bidB float64) bool {
+	return zones.Less(
+		auctiontypes.StartAuctionBid{Zone: zoneA, Bid: bidA},
+		auctiontypes.StartAuctionBid{Zone: zoneB, Bid: bidB},
+	)
+}
+
+func releaseBatch(client auctiontypes.RepPoolClient, placements map[string][]auctiontypes.StartAuctionInfo, reserved map[string]string) {
+	wg := &sync.WaitGroup{}
+	for repGuid, auctionInfos := range placements {
+		for _, auctionInfo := range auctionInfos {
+			if reserved[auctionInfo.InstanceGuid] != repGuid {
+				continue
+			}
+
+			wg.Add(1)
+			go func(repGuid string, auctionInfo auctiontypes.StartAuctionInfo) {
+				client.ReleaseReservation([]string{repGuid}, auctionInfo)
+				wg.Done()
+			}(repGuid, auctionInfo)
+		}
+	}
+	wg.Wait()
+}
+
+// the batch shares its communications; spread them so that the results add up to the total
+func distributeCommunications(results []auctiontypes.StartAuctionResult, numCommunications int) {
+	if len(results) == 0 {
+		return
+	}
+
+	share, remainder := numCommunications/len(results), numCommunications%len(results)
+	for i := range results {
+		results[i].NumCommunications = share
+		if i < remainder {
+			results[i].NumCommunications += 1
+		}
+	}
+}
+
+type byMemoryDescending struct {
+	auctionRequest auctiontypes.StartAuctionBatchRequest
+	indices        []int
+}
+
+func (b byMemoryDescending) Len() int      { return len(b.indices) }
+func (b byMemoryDescending) Swap(i, j int) { b.indices[i], b.indices[j] = b.indices[j], b.indices[i] }
+func (b byMemoryDescending) Less(i, j int) bool {
+	return b.auctionRequest.LRPStartAuctions[b.indices[i]].MemoryMB > b.auctionRequest.LRPStartAuctions[b.indices[j]].MemoryMB
+}
@@ -0,0 +1,83 @@
+package auctionrunner
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+// constraintFailureClient remembers whether every bid the algorithm saw failed on the instance's placement constraints,
+// or on reps too busy or draining to take it on, so the runner can tell "nobody has room" apart from "nobody may run this",
+// "try again later" and "these reps are on their way out"
+type constraintFailureClient struct {
+	auctiontypes.RepPoolClient
+
+	lock                    *sync.Mutex
+	sawConstraintFailure    bool
+	sawBusyFailure          bool
+	sawDrainingFailure      bool
+	sawAnyOtherBidOrFailure bool
+}
+
+func newConstraintFailureClient(client auctiontypes.RepPoolClient) *constraintFailureClient {
+	return &constraintFailureClient{
+		RepPoolClient: client,
+		lock:          &sync.Mutex{},
+	}
+}
+
+func (c *constraintFailureClient) BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.RepPoolClient.BidForStartAuction(repGuids, startAuctionInfo)
+	c.record(bids)
+	return bids
+}
+
+func (c *constraintFailureClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.RepPoolClient.RebidThenTentativelyReserve(repGuids, startAuctionInfo)
+	c.record(bids)
+	return bids
+}
+
+func (c *constraintFailureClient) onlyConstraintFailures() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.sawConstraintFailure && !c.sawBusyFailure && !c.sawDrainingFailure && !c.sawAnyOtherBidOrFailure
+}
+
+// softFailure is RepBusy or RepDraining if those, and constraint failures, are all the algorithm saw, and nil otherwise.
+// Neither says anything about room: busy reps may well have some once they are done with what they are doing,
+// and draining reps turn everything away. Busy wins, as it is the one worth retrying.
+func (c *constraintFailureClient) softFailure() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch {
+	case c.sawAnyOtherBidOrFailure:
+		return nil
+	case c.sawBusyFailure:
+		return auctiontypes.RepBusy
+	case c.sawDrainingFailure:
+		return auctiontypes.RepDraining
+	default:
+		return nil
+	}
+}
+
+func (c *constraintFailureClient) record(bids auctiontypes.StartAuctionBids) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, bid := range bids {
+		switch bid.Error {
+		case auctiontypes.ConstraintNotSatisfied.Error():
+			c.sawConstraintFailure = true
+		case auctiontypes.RepBusy.Error():
+			c.sawBusyFailure = true
+		case auctiontypes.RepDraining.Error():
+			c.sawDrainingFailure = true
+		default:
+			c.sawAnyOtherBidOrFailure = true
+		}
+	}
+}
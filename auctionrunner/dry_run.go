@@ -0,0 +1,84 @@
+package auctionrunner
+
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+/*
+
+Get the bids from the subset of reps the algorithm would ask
+    Rank them: the winner is whoever the algorithm would try to reserve first
+        If they're all full, ask another subset
+
+A dry run never reserves, so it can't see a reservation fail: it reports
+where the instance would land if the rep still had room when asked.
+
+*/
+
+// all_rebid, all_reserve, pick_best and reserve_n_best all settle on the best bid of their bidding pool
+func dryRunBestBid(ctx context.Context, bidder StartAuctionBidder, auctionRequest auctiontypes.StartAuctionRequest) (string, auctiontypes.StartAuctionBids, int, int) {
+	return dryRunBiddingPool(ctx, bidder, auctionRequest, func(ranked auctiontypes.StartAuctionBids) string {
+		return ranked[0].Rep
+	})
+}
+
+func dryRunPickAmongBest(ctx context.Context, bidder StartAuctionBidder, auctionRequest auctiontypes.StartAuctionRequest) (string, auctiontypes.StartAuctionBids, int, int) {
+	return dryRunBiddingPool(ctx, bidder, auctionRequest, func(ranked auctiontypes.StartAuctionBids) string {
+		max := 5
+		if len(ranked.FilterErrors()) < max {
+			max = len(ranked.FilterErrors())
+		}
+		return ranked[:max].Shuffle()[0].Rep
+	})
+}
+
+func dryRunPickTwo(ctx context.Context, bidder StartAuctionBidder, auctionRequest auctiontypes.StartAuctionRequest) (string, auctiontypes.StartAuctionBids, int, int) {
+	rounds, numCommunications := 1, 0
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	numChoices := auctionRequest.Rules.NumChoices
+	if numChoices <= 0 {
+		numChoices = defaultNumChoices
+	}
+
+	var ranked auctiontypes.StartAuctionBids
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		sampledReps := auctionRequest.RepGuids.RandomSubsetByCount(numChoices)
+
+		numCommunications += len(sampledReps)
+		scores := bidder.BidForStartAuction(sampledReps, auctionInfo)
+		ranked = scores.Ranked()
+		if scores.AllFailed() {
+			continue
+		}
+
+		return ranked[0].Rep, ranked, rounds, numCommunications
+	}
+
+	return "", ranked, rounds, numCommunications
+}
+
+func dryRunBiddingPool(ctx context.Context, bidder StartAuctionBidder, auctionRequest auctiontypes.StartAuctionRequest, pickWinner func(auctiontypes.StartAuctionBids) string) (string, auctiontypes.StartAuctionBids, int, int) {
+	rounds, numCommunications := 1, 0
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	var ranked auctiontypes.StartAuctionBids
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		//pick a subset
+		firstRoundReps := auctionRequest.RepGuids.RandomSubsetByFraction(auctionRequest.Rules.MaxBiddingPoolFraction, auctionRequest.Rules.MinBiddingPool)
+
+		//get everyone's bid, if they're all full: bail
+		numCommunications += len(firstRoundReps)
+		firstRoundScores := bidder.BidForStartAuction(firstRoundReps, auctionInfo)
+		ranked = firstRoundScores.Ranked()
+		if firstRoundScores.AllFailed() {
+			continue
+		}
+
+		return pickWinner(ranked), ranked, rounds, numCommunications
+	}
+
+	return "", ranked, rounds, numCommunications
+}
@@ -0,0 +1,126 @@
+package auctionrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/runtime-schema/models"
+)
+
+/*
+
+Set the rep draining, so it refuses to bid for (or reserve) any new instance
+    For each instance it runs, one at a time
+        Auction the instance among the other reps
+            If one runs it, stop the instance on the draining rep
+            If nobody would, leave it running where it is
+
+Moving one instance at a time means at most one of them runs twice at any moment,
+and the rest of the pool takes on the rep's load gradually.
+The rep stays draining afterwards, whether or not everything moved: SetDraining(repGuid, false) puts it back in service.
+
+*/
+
+// EvacuationObserver is told how each instance of an evacuation went as soon as it is known.
+// Observers passed to New that also implement it are told.
+type EvacuationObserver interface {
+	InstanceEvacuated(repGuid string, evacuation auctiontypes.InstanceEvacuation)
+}
+
+func (a *auctionRunner) RunEvacuation(evacuationRequest auctiontypes.EvacuationRequest) (auctiontypes.EvacuationResult, error) {
+	return a.RunEvacuationWithContext(context.Background(), evacuationRequest)
+}
+
+func (a *auctionRunner) RunEvacuationWithContext(ctx context.Context, evacuationRequest auctiontypes.EvacuationRequest) (result auctiontypes.EvacuationResult, err error) {
+	result = auctiontypes.EvacuationResult{
+		RepGuid: evacuationRequest.RepGuid,
+	}
+
+	t := time.Now()
+	defer func() {
+		result.Duration = time.Since(t)
+	}()
+
+	err = a.client.SetDraining(evacuationRequest.RepGuid, true)
+	if err != nil {
+		return result, err
+	}
+
+	repGuids := evacuationRequest.RepGuids.Without(evacuationRequest.RepGuid)
+
+	for _, lrpStartAuction := range evacuationRequest.LRPStartAuctions {
+		evacuation := a.evacuateInstance(ctx, evacuationRequest, repGuids, lrpStartAuction)
+		if evacuation.Error != "" {
+			err = auctiontypes.EvacuationIncomplete
+		}
+		instancesEvacuated.Inc(evacuationOutcome(evacuation))
+
+		result.Instances = append(result.Instances, evacuation)
+		for _, observer := range a.observers {
+			evacuationObserver, ok := observer.(EvacuationObserver)
+			if ok {
+				evacuationObserver.InstanceEvacuated(evacuationRequest.RepGuid, evacuation)
+			}
+		}
+	}
+
+	if err != nil && ctx.Err() != nil {
+		err = contextError(ctx)
+	}
+
+	return result, err
+}
+
+func (a *auctionRunner) evacuateInstance(ctx context.Context, evacuationRequest auctiontypes.EvacuationRequest, repGuids auctiontypes.RepGuids, lrpStartAuction models.LRPStartAuction) auctiontypes.InstanceEvacuation {
+	evacuation := auctiontypes.InstanceEvacuation{
+		LRPStartAuction: lrpStartAuction,
+	}
+
+	//out of time: leave the rest where they are
+	if ctx.Err() != nil {
+		evacuation.Error = contextError(ctx).Error()
+		return evacuation
+	}
+
+	processGuid := lrpStartAuction.ProcessGuid
+	startResult, err := a.RunLRPStartAuctionWithContext(ctx, auctiontypes.StartAuctionRequest{
+		LRPStartAuction:    lrpStartAuction,
+		RepGuids:           repGuids,
+		Rules:              evacuationRequest.Rules,
+		RequiredAttributes: evacuationRequest.RequiredAttributesByProcessGuid[processGuid],
+		AffinityRules:      evacuationRequest.AffinityRulesByProcessGuid[processGuid],
+		Resources:          evacuationRequest.ResourcesByProcessGuid[processGuid],
+	})
+	evacuation.Winner = startResult.Winner
+	evacuation.TraceID = startResult.TraceID
+	if err != nil {
+		evacuation.Error = err.Error()
+		return evacuation
+	}
+
+	//only now that another rep runs it; under the trace ID of the auction that moved it
+	err = tracedClient(a.client, startResult.TraceID).Stop(evacuationRequest.RepGuid, models.StopLRPInstance{
+		ProcessGuid:  lrpStartAuction.ProcessGuid,
+		InstanceGuid: lrpStartAuction.InstanceGuid,
+		Index:        lrpStartAuction.Index,
+	})
+	if err != nil {
+		evacuation.Error = err.Error()
+		return evacuation
+	}
+
+	evacuation.Stopped = true
+	return evacuation
+}
+
+func evacuationOutcome(evacuation auctiontypes.InstanceEvacuation) string {
+	switch {
+	case evacuation.Stopped:
+		return "moved"
+	case evacuation.Winner != "":
+		return "failed-to-stop"
+	default:
+		return "not-placed"
+	}
+}
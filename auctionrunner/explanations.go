@@ -0,0 +1,55 @@
+package auctionrunner
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+// explanationClient remembers the last explanation each rep gave for its bid,
+// so the runner can tell why the winner won: the explanation of the bid it reserved with
+type explanationClient struct {
+	auctiontypes.RepPoolClient
+
+	lock         *sync.Mutex
+	explanations map[string]*auctiontypes.BidExplanation
+}
+
+func newExplanationClient(client auctiontypes.RepPoolClient) *explanationClient {
+	return &explanationClient{
+		RepPoolClient: client,
+		lock:          &sync.Mutex{},
+		explanations:  map[string]*auctiontypes.BidExplanation{},
+	}
+}
+
+func (c *explanationClient) BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.RepPoolClient.BidForStartAuction(repGuids, startAuctionInfo)
+	c.record(bids)
+	return bids
+}
+
+func (c *explanationClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.RepPoolClient.RebidThenTentativelyReserve(repGuids, startAuctionInfo)
+	c.record(bids)
+	return bids
+}
+
+// nil if the rep did not explain itself, e.g. because it predates explanations
+func (c *explanationClient) explanationFor(repGuid string) *auctiontypes.BidExplanation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.explanations[repGuid]
+}
+
+func (c *explanationClient) record(bids auctiontypes.StartAuctionBids) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, bid := range bids {
+		if bid.Explanation != nil {
+			c.explanations[bid.Rep] = bid.Explanation
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package fake_auctionrunner
 
 import (
+	"context"
 	. "github.com/cloudfoundry-incubator/auction/auctiontypes"
 	"sync"
 )
@@ -25,6 +26,64 @@ type FakeAuctionRunner struct {
 		result1 StopAuctionResult
 		result2 error
 	}
+	RunLRPStartAuctionBatchStub        func(auctionRequest StartAuctionBatchRequest) ([]StartAuctionResult, error)
+	runLRPStartAuctionBatchMutex       sync.RWMutex
+	runLRPStartAuctionBatchArgsForCall []struct {
+		arg1 StartAuctionBatchRequest
+	}
+	runLRPStartAuctionBatchReturns struct {
+		result1 []StartAuctionResult
+		result2 error
+	}
+	RunLRPStartAuctionWithContextStub        func(ctx context.Context, auctionRequest StartAuctionRequest) (StartAuctionResult, error)
+	runLRPStartAuctionWithContextMutex       sync.RWMutex
+	runLRPStartAuctionWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 StartAuctionRequest
+	}
+	runLRPStartAuctionWithContextReturns struct {
+		result1 StartAuctionResult
+		result2 error
+	}
+	RunLRPStopAuctionWithContextStub        func(ctx context.Context, auctionRequest StopAuctionRequest) (StopAuctionResult, error)
+	runLRPStopAuctionWithContextMutex       sync.RWMutex
+	runLRPStopAuctionWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 StopAuctionRequest
+	}
+	runLRPStopAuctionWithContextReturns struct {
+		result1 StopAuctionResult
+		result2 error
+	}
+	RunLRPStartAuctionBatchWithContextStub        func(ctx context.Context, auctionRequest StartAuctionBatchRequest) ([]StartAuctionResult, error)
+	runLRPStartAuctionBatchWithContextMutex       sync.RWMutex
+	runLRPStartAuctionBatchWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 StartAuctionBatchRequest
+	}
+	runLRPStartAuctionBatchWithContextReturns struct {
+		result1 []StartAuctionResult
+		result2 error
+	}
+	RunEvacuationStub        func(evacuationRequest EvacuationRequest) (EvacuationResult, error)
+	runEvacuationMutex       sync.RWMutex
+	runEvacuationArgsForCall []struct {
+		arg1 EvacuationRequest
+	}
+	runEvacuationReturns struct {
+		result1 EvacuationResult
+		result2 error
+	}
+	RunEvacuationWithContextStub        func(ctx context.Context, evacuationRequest EvacuationRequest) (EvacuationResult, error)
+	runEvacuationWithContextMutex       sync.RWMutex
+	runEvacuationWithContextArgsForCall []struct {
+		arg1 context.Context
+		arg2 EvacuationRequest
+	}
+	runEvacuationWithContextReturns struct {
+		result1 EvacuationResult
+		result2 error
+	}
 }
 
 func (fake *FakeAuctionRunner) RunLRPStartAuction(arg1 StartAuctionRequest) (StartAuctionResult, error) {
@@ -91,4 +150,200 @@ func (fake *FakeAuctionRunner) RunLRPStopAuctionReturns(result1 StopAuctionResul
 	}{result1, result2}
 }
 
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatch(arg1 StartAuctionBatchRequest) ([]StartAuctionResult, error) {
+	fake.runLRPStartAuctionBatchMutex.Lock()
+	defer fake.runLRPStartAuctionBatchMutex.Unlock()
+	fake.runLRPStartAuctionBatchArgsForCall = append(fake.runLRPStartAuctionBatchArgsForCall, struct {
+		arg1 StartAuctionBatchRequest
+	}{arg1})
+	if fake.RunLRPStartAuctionBatchStub != nil {
+		return fake.RunLRPStartAuctionBatchStub(arg1)
+	} else {
+		return fake.runLRPStartAuctionBatchReturns.result1, fake.runLRPStartAuctionBatchReturns.result2
+	}
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatchCallCount() int {
+	fake.runLRPStartAuctionBatchMutex.RLock()
+	defer fake.runLRPStartAuctionBatchMutex.RUnlock()
+	return len(fake.runLRPStartAuctionBatchArgsForCall)
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatchArgsForCall(i int) StartAuctionBatchRequest {
+	fake.runLRPStartAuctionBatchMutex.RLock()
+	defer fake.runLRPStartAuctionBatchMutex.RUnlock()
+	return fake.runLRPStartAuctionBatchArgsForCall[i].arg1
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatchReturns(result1 []StartAuctionResult, result2 error) {
+	fake.runLRPStartAuctionBatchReturns = struct {
+		result1 []StartAuctionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionWithContext(arg1 context.Context, arg2 StartAuctionRequest) (StartAuctionResult, error) {
+	fake.runLRPStartAuctionWithContextMutex.Lock()
+	defer fake.runLRPStartAuctionWithContextMutex.Unlock()
+	fake.runLRPStartAuctionWithContextArgsForCall = append(fake.runLRPStartAuctionWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 StartAuctionRequest
+	}{arg1, arg2})
+	if fake.RunLRPStartAuctionWithContextStub != nil {
+		return fake.RunLRPStartAuctionWithContextStub(arg1, arg2)
+	} else {
+		return fake.runLRPStartAuctionWithContextReturns.result1, fake.runLRPStartAuctionWithContextReturns.result2
+	}
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionWithContextCallCount() int {
+	fake.runLRPStartAuctionWithContextMutex.RLock()
+	defer fake.runLRPStartAuctionWithContextMutex.RUnlock()
+	return len(fake.runLRPStartAuctionWithContextArgsForCall)
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionWithContextArgsForCall(i int) (context.Context, StartAuctionRequest) {
+	fake.runLRPStartAuctionWithContextMutex.RLock()
+	defer fake.runLRPStartAuctionWithContextMutex.RUnlock()
+	return fake.runLRPStartAuctionWithContextArgsForCall[i].arg1, fake.runLRPStartAuctionWithContextArgsForCall[i].arg2
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionWithContextReturns(result1 StartAuctionResult, result2 error) {
+	fake.runLRPStartAuctionWithContextReturns = struct {
+		result1 StartAuctionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuctionRunner) RunLRPStopAuctionWithContext(arg1 context.Context, arg2 StopAuctionRequest) (StopAuctionResult, error) {
+	fake.runLRPStopAuctionWithContextMutex.Lock()
+	defer fake.runLRPStopAuctionWithContextMutex.Unlock()
+	fake.runLRPStopAuctionWithContextArgsForCall = append(fake.runLRPStopAuctionWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 StopAuctionRequest
+	}{arg1, arg2})
+	if fake.RunLRPStopAuctionWithContextStub != nil {
+		return fake.RunLRPStopAuctionWithContextStub(arg1, arg2)
+	} else {
+		return fake.runLRPStopAuctionWithContextReturns.result1, fake.runLRPStopAuctionWithContextReturns.result2
+	}
+}
+
+func (fake *FakeAuctionRunner) RunLRPStopAuctionWithContextCallCount() int {
+	fake.runLRPStopAuctionWithContextMutex.RLock()
+	defer fake.runLRPStopAuctionWithContextMutex.RUnlock()
+	return len(fake.runLRPStopAuctionWithContextArgsForCall)
+}
+
+func (fake *FakeAuctionRunner) RunLRPStopAuctionWithContextArgsForCall(i int) (context.Context, StopAuctionRequest) {
+	fake.runLRPStopAuctionWithContextMutex.RLock()
+	defer fake.runLRPStopAuctionWithContextMutex.RUnlock()
+	return fake.runLRPStopAuctionWithContextArgsForCall[i].arg1, fake.runLRPStopAuctionWithContextArgsForCall[i].arg2
+}
+
+func (fake *FakeAuctionRunner) RunLRPStopAuctionWithContextReturns(result1 StopAuctionResult, result2 error) {
+	fake.runLRPStopAuctionWithContextReturns = struct {
+		result1 StopAuctionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatchWithContext(arg1 context.Context, arg2 StartAuctionBatchRequest) ([]StartAuctionResult, error) {
+	fake.runLRPStartAuctionBatchWithContextMutex.Lock()
+	defer fake.runLRPStartAuctionBatchWithContextMutex.Unlock()
+	fake.runLRPStartAuctionBatchWithContextArgsForCall = append(fake.runLRPStartAuctionBatchWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 StartAuctionBatchRequest
+	}{arg1, arg2})
+	if fake.RunLRPStartAuctionBatchWithContextStub != nil {
+		return fake.RunLRPStartAuctionBatchWithContextStub(arg1, arg2)
+	} else {
+		return fake.runLRPStartAuctionBatchWithContextReturns.result1, fake.runLRPStartAuctionBatchWithContextReturns.result2
+	}
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatchWithContextCallCount() int {
+	fake.runLRPStartAuctionBatchWithContextMutex.RLock()
+	defer fake.runLRPStartAuctionBatchWithContextMutex.RUnlock()
+	return len(fake.runLRPStartAuctionBatchWithContextArgsForCall)
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatchWithContextArgsForCall(i int) (context.Context, StartAuctionBatchRequest) {
+	fake.runLRPStartAuctionBatchWithContextMutex.RLock()
+	defer fake.runLRPStartAuctionBatchWithContextMutex.RUnlock()
+	return fake.runLRPStartAuctionBatchWithContextArgsForCall[i].arg1, fake.runLRPStartAuctionBatchWithContextArgsForCall[i].arg2
+}
+
+func (fake *FakeAuctionRunner) RunLRPStartAuctionBatchWithContextReturns(result1 []StartAuctionResult, result2 error) {
+	fake.runLRPStartAuctionBatchWithContextReturns = struct {
+		result1 []StartAuctionResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuctionRunner) RunEvacuation(arg1 EvacuationRequest) (EvacuationResult, error) {
+	fake.runEvacuationMutex.Lock()
+	defer fake.runEvacuationMutex.Unlock()
+	fake.runEvacuationArgsForCall = append(fake.runEvacuationArgsForCall, struct {
+		arg1 EvacuationRequest
+	}{arg1})
+	if fake.RunEvacuationStub != nil {
+		return fake.RunEvacuationStub(arg1)
+	} else {
+		return fake.runEvacuationReturns.result1, fake.runEvacuationReturns.result2
+	}
+}
+
+func (fake *FakeAuctionRunner) RunEvacuationCallCount() int {
+	fake.runEvacuationMutex.RLock()
+	defer fake.runEvacuationMutex.RUnlock()
+	return len(fake.runEvacuationArgsForCall)
+}
+
+func (fake *FakeAuctionRunner) RunEvacuationArgsForCall(i int) EvacuationRequest {
+	fake.runEvacuationMutex.RLock()
+	defer fake.runEvacuationMutex.RUnlock()
+	return fake.runEvacuationArgsForCall[i].arg1
+}
+
+func (fake *FakeAuctionRunner) RunEvacuationReturns(result1 EvacuationResult, result2 error) {
+	fake.runEvacuationReturns = struct {
+		result1 EvacuationResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuctionRunner) RunEvacuationWithContext(arg1 context.Context, arg2 EvacuationRequest) (EvacuationResult, error) {
+	fake.runEvacuationWithContextMutex.Lock()
+	defer fake.runEvacuationWithContextMutex.Unlock()
+	fake.runEvacuationWithContextArgsForCall = append(fake.runEvacuationWithContextArgsForCall, struct {
+		arg1 context.Context
+		arg2 EvacuationRequest
+	}{arg1, arg2})
+	if fake.RunEvacuationWithContextStub != nil {
+		return fake.RunEvacuationWithContextStub(arg1, arg2)
+	} else {
+		return fake.runEvacuationWithContextReturns.result1, fake.runEvacuationWithContextReturns.result2
+	}
+}
+
+func (fake *FakeAuctionRunner) RunEvacuationWithContextCallCount() int {
+	fake.runEvacuationWithContextMutex.RLock()
+	defer fake.runEvacuationWithContextMutex.RUnlock()
+	return len(fake.runEvacuationWithContextArgsForCall)
+}
+
+func (fake *FakeAuctionRunner) RunEvacuationWithContextArgsForCall(i int) (context.Context, EvacuationRequest) {
+	fake.runEvacuationWithContextMutex.RLock()
+	defer fake.runEvacuationWithContextMutex.RUnlock()
+	return fake.runEvacuationWithContextArgsForCall[i].arg1, fake.runEvacuationWithContextArgsForCall[i].arg2
+}
+
+func (fake *FakeAuctionRunner) RunEvacuationWithContextReturns(result1 EvacuationResult, result2 error) {
+	fake.runEvacuationWithContextReturns = struct {
+		result1 EvacuationResult
+		result2 error
+	}{result1, result2}
+}
+
 var _ AuctionRunner = new(FakeAuctionRunner)
@@ -0,0 +1,111 @@
+package auctionrunner
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/runtime-schema/models"
+)
+
+//why a round did not place the instance
+const (
+	abandonedNoBids         = "every rep failed to bid"
+	abandonedNoReservations = "every rep asked failed to reserve"
+	abandonedOutbid         = "another rep outbid the reserved winner"
+	abandonedOutOfTime      = "the auction ran out of time"
+	abandonedRunFailed      = "every reserved rep failed to run the instance"
+	abandonedEvictionFailed = "the reserved winner failed to evict its victims"
+)
+
+// history observes the rounds of one start auction when StartAuctionRules.RecordHistory is set
+type history struct {
+	lock   *sync.Mutex
+	rounds []auctiontypes.StartAuctionRound
+}
+
+func newHistory() *history {
+	return &history{
+		lock: &sync.Mutex{},
+	}
+}
+
+func (h *history) Rounds() []auctiontypes.StartAuctionRound {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.rounds
+}
+
+func (h *history) RoundStarted(startAuction models.LRPStartAuction, round int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.rounds = append(h.rounds, auctiontypes.StartAuctionRound{Round: round})
+}
+
+func (h *history) BidsReceived(startAuction models.LRPStartAuction, repGuids []string, bids auctiontypes.StartAuctionBids) {
+	h.update(func(round *auctiontypes.StartAuctionRound) {
+		round.Sampled = appendMissing(round.Sampled, repGuids...)
+		round.Bids = append(round.Bids, bids...)
+	})
+}
+
+func (h *history) ReservationAttempted(startAuction models.LRPStartAuction, repGuids []string, bids auctiontypes.StartAuctionBids) {
+	h.update(func(round *auctiontypes.StartAuctionRound) {
+		round.Sampled = appendMissing(round.Sampled, repGuids...)
+		round.Reserved = append(round.Reserved, repGuids...)
+		round.Bids = append(round.Bids, bids...)
+	})
+}
+
+func (h *history) ReservationReleased(startAuction models.LRPStartAuction, repGuids []string) {
+	h.update(func(round *auctiontypes.StartAuctionRound) {
+		round.Released = append(round.Released, repGuids...)
+	})
+}
+
+func (h *history) Ran(startAuction models.LRPStartAuction, repGuid string, err error) {
+	if err != nil {
+		return
+	}
+	h.update(func(round *auctiontypes.StartAuctionRound) {
+		round.Ran = repGuid
+	})
+}
+
+func (h *history) RoundAbandoned(startAuction models.LRPStartAuction, number int, reason string) {
+	h.update(func(round *auctiontypes.StartAuctionRound) {
+		round.Abandoned = reason
+	})
+}
+
+func (h *history) AuctionFinished(result auctiontypes.StartAuctionResult, err error) {}
+
+// algorithms that don't mark their rounds get everything recorded in one
+func (h *history) update(f func(round *auctiontypes.StartAuctionRound)) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if len(h.rounds) == 0 {
+		h.rounds = append(h.rounds, auctiontypes.StartAuctionRound{Round: 1})
+	}
+
+	f(&h.rounds[len(h.rounds)-1])
+}
+
+func appendMissing(repGuids []string, more ...string) []string {
+	for _, repGuid := range more {
+		found := false
+		for _, existing := range repGuids {
+			if existing == repGuid {
+				found = true
+				break
+			}
+		}
+		if !found {
+			repGuids = append(repGuids, repGuid)
+		}
+	}
+
+	return repGuids
+}
@@ -0,0 +1,44 @@
+package auctionrunner
+
+import "sync"
+
+// inFlight tracks the instances a runner is auctioning so that a resubmitted instance is not placed twice.
+// It belongs to a single runner: auctioneers that do not share a runner do not see each other's claims,
+// so a resubmission that reaches another auctioneer is only turned away if it lands on the rep that already holds the instance.
+type inFlight struct {
+	lock          *sync.Mutex
+	instanceGuids map[string]bool
+}
+
+func newInFlight() *inFlight {
+	return &inFlight{
+		lock:          &sync.Mutex{},
+		instanceGuids: map[string]bool{},
+	}
+}
+
+// claim reports, for each instance, whether the caller may auction it; duplicates among the instances lose too
+func (f *inFlight) claim(instanceGuids ...string) []bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	claimed := make([]bool, len(instanceGuids))
+	for i, instanceGuid := range instanceGuids {
+		if f.instanceGuids[instanceGuid] {
+			continue
+		}
+		f.instanceGuids[instanceGuid] = true
+		claimed[i] = true
+	}
+
+	return claimed
+}
+
+func (f *inFlight) release(instanceGuids ...string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, instanceGuid := range instanceGuids {
+		delete(f.instanceGuids, instanceGuid)
+	}
+}
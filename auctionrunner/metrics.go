@@ -0,0 +1,48 @@
+package auctionrunner
+
+import (
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/auction/metrics"
+)
+
+var (
+	startAuctionsStarted       = metrics.Default.Counter("auction_start_auctions_started_total", "Start auctions begun.", "algorithm")
+	startAuctionsSucceeded     = metrics.Default.Counter("auction_start_auctions_succeeded_total", "Start auctions that placed their instance.", "algorithm")
+	startAuctionsFailed        = metrics.Default.Counter("auction_start_auctions_failed_total", "Start auctions that did not place their instance.", "algorithm", "reason")
+	startAuctionsDuplicated    = metrics.Default.Counter("auction_start_auctions_duplicated_total", "Start auctions turned away because their instance was already being auctioned.", "algorithm")
+	startAuctionRounds         = metrics.Default.Histogram("auction_start_auction_rounds", "Rounds held per start auction.", []float64{1, 2, 3, 5, 10, 20, 40}, "algorithm")
+	startAuctionCommunications = metrics.Default.Histogram("auction_start_auction_communications", "Messages sent to reps per start auction.", []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000}, "algorithm")
+	instancesEvacuated         = metrics.Default.Counter("auction_instances_evacuated_total", "Instances an evacuation tried to move off a draining rep.", "outcome")
+)
+
+// batched instances are counted under this algorithm
+const batchAlgorithm = "batch"
+
+func recordStartAuction(algorithm string, result auctiontypes.StartAuctionResult, err error) {
+	if err != nil {
+		startAuctionsFailed.Inc(algorithm, failureReason(err))
+	} else {
+		startAuctionsSucceeded.Inc(algorithm)
+	}
+	startAuctionRounds.Observe(float64(result.NumRounds), algorithm)
+	startAuctionCommunications.Observe(float64(result.NumCommunications), algorithm)
+}
+
+func failureReason(err error) string {
+	switch err {
+	case auctiontypes.InsufficientResources:
+		return "insufficient-resources"
+	case auctiontypes.ConstraintNotSatisfied:
+		return "constraint-not-satisfied"
+	case auctiontypes.AuctionDeadlineExceeded:
+		return "deadline-exceeded"
+	case auctiontypes.AuctionCancelled:
+		return "cancelled"
+	case auctiontypes.RepBusy:
+		return "busy"
+	case auctiontypes.RepDraining:
+		return "draining"
+	default:
+		return "other"
+	}
+}
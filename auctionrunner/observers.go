@@ -0,0 +1,128 @@
+package auctionrunner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/runtime-schema/models"
+)
+
+// Observer is told of every step of the start auctions a runner holds (see New), e.g. to audit or debug them.
+// Observers are called synchronously from the auctions' goroutines, often concurrently: they must be quick and safe for concurrent use.
+// Batched auctions are only reported to AuctionFinished; dry runs are not reported at all.
+type Observer interface {
+	RoundStarted(startAuction models.LRPStartAuction, round int)
+	//bids may be missing for reps that did not answer in time
+	BidsReceived(startAuction models.LRPStartAuction, repGuids []string, bids auctiontypes.StartAuctionBids)
+	//the reps whose bids carry no error now hold a reservation
+	ReservationAttempted(startAuction models.LRPStartAuction, repGuids []string, bids auctiontypes.StartAuctionBids)
+	ReservationReleased(startAuction models.LRPStartAuction, repGuids []string)
+	Ran(startAuction models.LRPStartAuction, repGuid string, err error)
+	RoundAbandoned(startAuction models.LRPStartAuction, round int, reason string)
+	AuctionFinished(result auctiontypes.StartAuctionResult, err error)
+}
+
+type observersKey struct{}
+
+// observers fans the steps of one start auction out to every observer;
+// algorithms mark the rounds through ctx, observerClient reports the rest
+type observers struct {
+	startAuction models.LRPStartAuction
+	observers    []Observer
+
+	lock  *sync.Mutex
+	round int
+}
+
+func withObservers(ctx context.Context, startAuction models.LRPStartAuction, list []Observer) (context.Context, *observers) {
+	o := &observers{
+		startAuction: startAuction,
+		observers:    list,
+		lock:         &sync.Mutex{},
+	}
+
+	return context.WithValue(ctx, observersKey{}, o), o
+}
+
+// startRound is a no-op unless the auction is observed
+func startRound(ctx context.Context) {
+	o, _ := ctx.Value(observersKey{}).(*observers)
+	if o == nil {
+		return
+	}
+
+	o.lock.Lock()
+	o.round++
+	round := o.round
+	o.lock.Unlock()
+
+	for _, observer := range o.observers {
+		observer.RoundStarted(o.startAuction, round)
+	}
+}
+
+// abandonRound is a no-op unless the auction is observed
+func abandonRound(ctx context.Context, reason string) {
+	o, _ := ctx.Value(observersKey{}).(*observers)
+	if o == nil {
+		return
+	}
+
+	o.lock.Lock()
+	round := o.round
+	o.lock.Unlock()
+
+	for _, observer := range o.observers {
+		observer.RoundAbandoned(o.startAuction, round, reason)
+	}
+}
+
+func (o *observers) auctionFinished(result auctiontypes.StartAuctionResult, err error) {
+	for _, observer := range o.observers {
+		observer.AuctionFinished(result, err)
+	}
+}
+
+type observerClient struct {
+	auctiontypes.RepPoolClient
+	observers *observers
+}
+
+func newObserverClient(client auctiontypes.RepPoolClient, o *observers) *observerClient {
+	return &observerClient{
+		RepPoolClient: client,
+		observers:     o,
+	}
+}
+
+func (c *observerClient) BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.RepPoolClient.BidForStartAuction(repGuids, startAuctionInfo)
+	for _, observer := range c.observers.observers {
+		observer.BidsReceived(c.observers.startAuction, repGuids, bids)
+	}
+	return bids
+}
+
+func (c *observerClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.RepPoolClient.RebidThenTentativelyReserve(repGuids, startAuctionInfo)
+	for _, observer := range c.observers.observers {
+		observer.ReservationAttempted(c.observers.startAuction, repGuids, bids)
+	}
+	return bids
+}
+
+func (c *observerClient) ReleaseReservation(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) {
+	c.RepPoolClient.ReleaseReservation(repGuids, startAuctionInfo)
+	for _, observer := range c.observers.observers {
+		observer.ReservationReleased(c.observers.startAuction, repGuids)
+	}
+}
+
+func (c *observerClient) Run(repGuid string, startAuction models.LRPStartAuction) error {
+	err := c.RepPoolClient.Run(repGuid, startAuction)
+	for _, observer := range c.observers.observers {
+		observer.Ran(startAuction, repGuid, err)
+	}
+	return err
+}
@@ -1,6 +1,10 @@
 package auctionrunner
 
-import "github.com/cloudfoundry-incubator/auction/auctiontypes"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
 
 /*
 
@@ -9,11 +13,13 @@ Get the bids from the subset of reps
 
 */
 
-func pickBestAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
+func pickBestAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
 	rounds, numCommunications := 1, 0
-	auctionInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(auctionRequest.LRPStartAuction)
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		startRound(ctx)
 
-	for ; rounds <= auctionRequest.Rules.MaxRounds; rounds++ {
 		//pick a subset
 		firstRoundReps := auctionRequest.RepGuids.RandomSubsetByFraction(auctionRequest.Rules.MaxBiddingPoolFraction, auctionRequest.Rules.MinBiddingPool)
 
@@ -21,21 +27,35 @@ func pickBestAuction(client auctiontypes.RepPoolClient, auctionRequest auctionty
 		numCommunications += len(firstRoundReps)
 		firstRoundScores := client.BidForStartAuction(firstRoundReps, auctionInfo)
 		if firstRoundScores.AllFailed() {
+			abandonRound(ctx, abandonedNoBids)
 			continue
 		}
 
-		winner := firstRoundScores.FilterErrors().Shuffle().Sort()[0]
+		winner := firstRoundScores.FilterErrors().Shuffle().SortBalancingZones(firstRoundScores.InstancesByZone())[0]
 
 		result := client.RebidThenTentativelyReserve([]string{winner.Rep}, auctionInfo)[0]
 		numCommunications += 1
 		if result.Error != "" {
+			abandonRound(ctx, abandonedNoReservations)
 			continue
 		}
 
-		client.Run(winner.Rep, auctionRequest.LRPStartAuction)
-		numCommunications += 1
+		//if the auction is out of time: release and bail
+		if ctx.Err() != nil {
+			client.ReleaseReservation([]string{winner.Rep}, auctionInfo)
+			numCommunications += 1
+			abandonRound(ctx, abandonedOutOfTime)
+			break
+		}
+
+		ran, n := runReserved(client, []string{winner.Rep}, auctionInfo, auctionRequest.LRPStartAuction)
+		numCommunications += n
+		if ran == "" {
+			abandonRound(ctx, abandonedRunFailed)
+			continue
+		}
 
-		return winner.Rep, rounds, numCommunications
+		return ran, rounds, numCommunications
 	}
 
 	return "", rounds, numCommunications
@@ -0,0 +1,70 @@
+package auctionrunner
+
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+/*
+
+Get the bids from d randomly sampled reps (the power of two choices when d is 2)
+	Select the best
+		If it fails to reserve, sample again
+
+*/
+
+const defaultNumChoices = 2
+
+func pickTwoAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
+	rounds, numCommunications := 1, 0
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	numChoices := auctionRequest.Rules.NumChoices
+	if numChoices <= 0 {
+		numChoices = defaultNumChoices
+	}
+
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		startRound(ctx)
+
+		//sample d reps
+		sampledReps := auctionRequest.RepGuids.RandomSubsetByCount(numChoices)
+
+		//get their bids, if they're all full: sample again
+		numCommunications += len(sampledReps)
+		scores := client.BidForStartAuction(sampledReps, auctionInfo)
+		if scores.AllFailed() {
+			abandonRound(ctx, abandonedNoBids)
+			continue
+		}
+
+		winner := scores.FilterErrors().Shuffle().SortBalancingZones(scores.InstancesByZone())[0]
+
+		result := client.RebidThenTentativelyReserve([]string{winner.Rep}, auctionInfo)[0]
+		numCommunications += 1
+		if result.Error != "" {
+			abandonRound(ctx, abandonedNoReservations)
+			continue
+		}
+
+		//if the auction is out of time: release and bail
+		if ctx.Err() != nil {
+			client.ReleaseReservation([]string{winner.Rep}, auctionInfo)
+			numCommunications += 1
+			abandonRound(ctx, abandonedOutOfTime)
+			break
+		}
+
+		ran, n := runReserved(client, []string{winner.Rep}, auctionInfo, auctionRequest.LRPStartAuction)
+		numCommunications += n
+		if ran == "" {
+			abandonRound(ctx, abandonedRunFailed)
+			continue
+		}
+
+		return ran, rounds, numCommunications
+	}
+
+	return "", rounds, numCommunications
+}
@@ -0,0 +1,105 @@
+package auctionrunner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/runtime-schema/models"
+)
+
+/*
+
+Get the bids from the subset of reps, letting full reps name the lower-priority instances they would evict
+    Select the best (the fewest evictions), balancing zones
+        Tell it to reserve, victims and all, so it holds the room across the eviction
+            Stop the victims it names now (if it cannot reserve or the auction is out of time: nobody is evicted)
+                If every victim stopped: tell it to run
+                Otherwise: release and try again
+
+*/
+
+func preemptionAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, []models.StopLRPInstance, int, int) {
+	rounds, numCommunications := 1, 0
+	evicted := []models.StopLRPInstance{}
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+	preemptingAuctionInfo := auctionInfo
+	preemptingAuctionInfo.Preempt = true
+
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		startRound(ctx)
+
+		//pick a subset
+		firstRoundReps := auctionRequest.RepGuids.RandomSubsetByFraction(auctionRequest.Rules.MaxBiddingPoolFraction, auctionRequest.Rules.MinBiddingPool)
+
+		//get everyone's bid, if nobody has anything to evict: bail
+		numCommunications += len(firstRoundReps)
+		firstRoundScores := client.BidForStartAuction(firstRoundReps, preemptingAuctionInfo)
+		if firstRoundScores.AllFailed() {
+			abandonRound(ctx, abandonedNoBids)
+			continue
+		}
+
+		winner := firstRoundScores.FilterErrors().Shuffle().SortBalancingZones(firstRoundScores.InstancesByZone())[0]
+
+		//hold the room first: the rep names its victims again as it reserves
+		reservation := client.RebidThenTentativelyReserve([]string{winner.Rep}, preemptingAuctionInfo)[0]
+		numCommunications += 1
+		if reservation.Error != "" {
+			abandonRound(ctx, abandonedNoReservations)
+			continue
+		}
+
+		//if the auction is out of time: release and bail
+		if ctx.Err() != nil {
+			client.ReleaseReservation([]string{winner.Rep}, auctionInfo)
+			numCommunications += 1
+			abandonRound(ctx, abandonedOutOfTime)
+			break
+		}
+
+		//make room, if some victim would not stop (e.g. another auction evicted it first) the instance does not fit: release
+		numCommunications += len(reservation.Victims)
+		stopped := stopVictims(client, reservation)
+		evicted = append(evicted, stopped...)
+		if len(stopped) < len(reservation.Victims) {
+			client.ReleaseReservation([]string{winner.Rep}, auctionInfo)
+			numCommunications += 1
+			abandonRound(ctx, abandonedEvictionFailed)
+			continue
+		}
+
+		ran, n := runReserved(client, []string{winner.Rep}, auctionInfo, auctionRequest.LRPStartAuction)
+		numCommunications += n
+		if ran == "" {
+			abandonRound(ctx, abandonedRunFailed)
+			continue
+		}
+
+		return ran, evicted, rounds, numCommunications
+	}
+
+	return "", evicted, rounds, numCommunications
+}
+
+func stopVictims(client auctiontypes.RepPoolClient, bid auctiontypes.StartAuctionBid) []models.StopLRPInstance {
+	lock := &sync.Mutex{}
+	stopped := []models.StopLRPInstance{}
+
+	wg := &sync.WaitGroup{}
+	for _, victim := range bid.Victims {
+		wg.Add(1)
+		go func(stopInstance models.StopLRPInstance) {
+			defer wg.Done()
+			if client.Stop(bid.Rep, stopInstance) != nil {
+				return
+			}
+			lock.Lock()
+			stopped = append(stopped, stopInstance)
+			lock.Unlock()
+		}(victim.StopLRPInstance())
+	}
+	wg.Wait()
+
+	return stopped
+}
@@ -1,6 +1,10 @@
 package auctionrunner
 
-import "github.com/cloudfoundry-incubator/auction/auctiontypes"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
 
 /*
 
@@ -10,22 +14,37 @@ Pick an arbitrary rep
 
 */
 
-func randomAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
+func randomAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
 	rounds, numCommunications := 1, 0
-	auctionInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(auctionRequest.LRPStartAuction)
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		startRound(ctx)
 
-	for ; rounds <= auctionRequest.Rules.MaxRounds; rounds++ {
 		randomPick := auctionRequest.RepGuids.RandomSubsetByCount(1)[0]
 		result := client.RebidThenTentativelyReserve([]string{randomPick}, auctionInfo)[0]
 		numCommunications += 1
 		if result.Error != "" {
+			abandonRound(ctx, abandonedNoReservations)
 			continue
 		}
 
-		client.Run(randomPick, auctionRequest.LRPStartAuction)
-		numCommunications += 1
+		//if the auction is out of time: release and bail
+		if ctx.Err() != nil {
+			client.ReleaseReservation([]string{randomPick}, auctionInfo)
+			numCommunications += 1
+			abandonRound(ctx, abandonedOutOfTime)
+			break
+		}
+
+		ran, n := runReserved(client, []string{randomPick}, auctionInfo, auctionRequest.LRPStartAuction)
+		numCommunications += n
+		if ran == "" {
+			abandonRound(ctx, abandonedRunFailed)
+			continue
+		}
 
-		return randomPick, rounds, numCommunications
+		return ran, rounds, numCommunications
 	}
 
 	return "", rounds, numCommunications
@@ -1,6 +1,10 @@
 package auctionrunner
 
-import "github.com/cloudfoundry-incubator/auction/auctiontypes"
+import (
+	"context"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
 
 /*
 
@@ -10,11 +14,13 @@ Get the bids from the subset of reps
 
 */
 
-func reserveNBestAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
+func reserveNBestAuction(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
 	rounds, numCommunications := 1, 0
-	auctionInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(auctionRequest.LRPStartAuction)
+	auctionInfo := auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest)
+
+	for ; rounds <= auctionRequest.Rules.MaxRounds && ctx.Err() == nil; rounds++ {
+		startRound(ctx)
 
-	for ; rounds <= auctionRequest.Rules.MaxRounds; rounds++ {
 		//pick a subset
 		firstRoundReps := auctionRequest.RepGuids.RandomSubsetByFraction(auctionRequest.Rules.MaxBiddingPoolFraction, auctionRequest.Rules.MinBiddingPool)
 
@@ -22,11 +28,13 @@ func reserveNBestAuction(client auctiontypes.RepPoolClient, auctionRequest aucti
 		numCommunications += len(firstRoundReps)
 		firstRoundScores := client.BidForStartAuction(firstRoundReps, auctionInfo)
 		if firstRoundScores.AllFailed() {
+			abandonRound(ctx, abandonedNoBids)
 			continue
 		}
 
 		// pick the top 5 winners
-		winners := firstRoundScores.FilterErrors().Shuffle().Sort()
+		zones := firstRoundScores.InstancesByZone()
+		winners := firstRoundScores.FilterErrors().Shuffle().SortBalancingZones(zones)
 		max := 5
 		if len(winners) < max {
 			max = len(winners)
@@ -38,19 +46,30 @@ func reserveNBestAuction(client auctiontypes.RepPoolClient, auctionRequest aucti
 		winners = client.RebidThenTentativelyReserve(winners.Reps(), auctionInfo)
 		//if they're all out of space, try again
 		if winners.AllFailed() {
+			abandonRound(ctx, abandonedNoReservations)
 			continue
 		}
 
 		//order by bid: the first is the winner, all others release
-		orderedReps := winners.FilterErrors().Shuffle().Sort().Reps()
+		orderedReps := winners.FilterErrors().Shuffle().SortBalancingZones(zones).Reps()
 
-		numCommunications += len(winners)
-		client.Run(orderedReps[0], auctionRequest.LRPStartAuction)
-		if len(orderedReps) > 1 {
-			client.ReleaseReservation(orderedReps[1:], auctionInfo)
+		//if the auction is out of time: release everyone and bail
+		if ctx.Err() != nil {
+			numCommunications += len(orderedReps)
+			client.ReleaseReservation(orderedReps, auctionInfo)
+			abandonRound(ctx, abandonedOutOfTime)
+			break
+		}
+
+		//run on the best, falling back to the next best if it fails
+		ran, n := runReserved(client, orderedReps, auctionInfo, auctionRequest.LRPStartAuction)
+		numCommunications += n
+		if ran == "" {
+			abandonRound(ctx, abandonedRunFailed)
+			continue
 		}
 
-		return orderedReps[0], rounds, numCommunications
+		return ran, rounds, numCommunications
 	}
 
 	return "", rounds, numCommunications
@@ -0,0 +1,119 @@
+package auctionrunner
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/runtime-schema/models"
+)
+
+// runFailureClient remembers every rep that failed to run the instance so the runner can report them;
+// those reps are not asked to bid again, lest a new round land the instance on them once more
+type runFailureClient struct {
+	auctiontypes.RepPoolClient
+
+	lock     *sync.Mutex
+	failures []auctiontypes.RepFailure
+}
+
+func newRunFailureClient(client auctiontypes.RepPoolClient) *runFailureClient {
+	return &runFailureClient{
+		RepPoolClient: client,
+		lock:          &sync.Mutex{},
+	}
+}
+
+func (c *runFailureClient) Run(repGuid string, startAuction models.LRPStartAuction) error {
+	err := c.RepPoolClient.Run(repGuid, startAuction)
+	if err != nil {
+		c.lock.Lock()
+		c.failures = append(c.failures, auctiontypes.RepFailure{
+			Rep:          repGuid,
+			InstanceGuid: startAuction.InstanceGuid,
+			Error:        err.Error(),
+		})
+		c.lock.Unlock()
+	}
+	return err
+}
+
+func (c *runFailureClient) BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	repGuids, bids := c.withoutFailedReps(repGuids)
+	if len(repGuids) == 0 {
+		return bids
+	}
+	return append(c.RepPoolClient.BidForStartAuction(repGuids, startAuctionInfo), bids...)
+}
+
+func (c *runFailureClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	repGuids, bids := c.withoutFailedReps(repGuids)
+	if len(repGuids) == 0 {
+		return bids
+	}
+	return append(c.RepPoolClient.RebidThenTentativelyReserve(repGuids, startAuctionInfo), bids...)
+}
+
+func (c *runFailureClient) runFailures() []auctiontypes.RepFailure {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.failures
+}
+
+// the reps that already failed to run the instance bid with their failure instead
+func (c *runFailureClient) withoutFailedReps(repGuids []string) ([]string, auctiontypes.StartAuctionBids) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	asked := []string{}
+	failedBids := auctiontypes.StartAuctionBids{}
+	for _, repGuid := range repGuids {
+		failure, failed := c.failureFor(repGuid)
+		if failed {
+			failedBids = append(failedBids, auctiontypes.StartAuctionBid{
+				Rep:   repGuid,
+				Error: failure.Error,
+			})
+		} else {
+			asked = append(asked, repGuid)
+		}
+	}
+
+	return asked, failedBids
+}
+
+func (c *runFailureClient) failureFor(repGuid string) (auctiontypes.RepFailure, bool) {
+	for _, failure := range c.failures {
+		if failure.Rep == repGuid {
+			return failure, true
+		}
+	}
+	return auctiontypes.RepFailure{}, false
+}
+
+// runReserved tells the reserved reps, best first, to run the instance until one does and releases the others.
+// It returns the rep running the instance ("" if none would) and the number of communications.
+func runReserved(client auctiontypes.RepPoolClient, orderedReps []string, auctionInfo auctiontypes.StartAuctionInfo, startAuction models.LRPStartAuction) (string, int) {
+	numCommunications := 0
+
+	for i, repGuid := range orderedReps {
+		numCommunications += 1
+		err := client.Run(repGuid, startAuction)
+		if err != nil {
+			//the rep may still be holding the room: free it and fall back to the next best
+			numCommunications += 1
+			client.ReleaseReservation([]string{repGuid}, auctionInfo)
+			continue
+		}
+
+		others := orderedReps[i+1:]
+		if len(others) > 0 {
+			numCommunications += len(others)
+			client.ReleaseReservation(others, auctionInfo)
+		}
+
+		return repGuid, numCommunications
+	}
+
+	return "", numCommunications
+}
@@ -1,13 +1,14 @@
 package auctionrunner
 
 import (
+	"context"
 	"sync"
 
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
 	"github.com/cloudfoundry-incubator/runtime-schema/models"
 )
 
-func stopAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StopAuctionRequest) (string, int, error) {
+func stopAuction(ctx context.Context, client auctiontypes.RepPoolClient, policy StopPolicy, auctionRequest auctiontypes.StopAuctionRequest) (string, []auctiontypes.RepFailure, int, error) {
 	numCommunication := 0
 
 	stopAuctionInfo := auctiontypes.StopAuctionInfo{
@@ -17,25 +18,25 @@ func stopAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.
 
 	numCommunication += len(auctionRequest.RepGuids)
 	stopAuctionBids := client.BidForStopAuction(auctionRequest.RepGuids, stopAuctionInfo)
+	zones := stopAuctionBids.InstancesByZone()
 	stopAuctionBids = stopAuctionBids.FilterErrors()
 
 	instanceGuids := stopAuctionBids.InstanceGuids()
 	if len(instanceGuids) <= 1 {
-		return "", numCommunication, auctiontypes.NothingToStop
+		return "", nil, numCommunication, auctiontypes.NothingToStop
+	}
+
+	//if the auction is out of time: bail before stopping anything
+	if ctx.Err() != nil {
+		return "", nil, numCommunication, contextError(ctx)
 	}
 
 	stopAuctionBids = stopAuctionBids.Shuffle()
 
-	var repGuidWithLoneRemainingInstance string
-	lowestScore := 1e9
+	repGuidWithLoneRemainingInstance := policy(zones, stopAuctionBids)
 
-	for _, stopAuctionBid := range stopAuctionBids {
-		bidIfRepGuidWins := stopAuctionBid.Bid - float64(len(stopAuctionBid.InstanceGuids)) + 1
-		if bidIfRepGuidWins < lowestScore {
-			lowestScore = bidIfRepGuidWins
-			repGuidWithLoneRemainingInstance = stopAuctionBid.Rep
-		}
-	}
+	lock := &sync.Mutex{}
+	stopFailures := []auctiontypes.RepFailure{}
 
 	wg := &sync.WaitGroup{}
 	for _, stopAuctionBid := range stopAuctionBids {
@@ -47,16 +48,25 @@ func stopAuction(client auctiontypes.RepPoolClient, auctionRequest auctiontypes.
 			numCommunication += 1
 			wg.Add(1)
 			go func(repGuid string, instanceGuid string) {
-				client.Stop(repGuid, models.StopLRPInstance{
+				defer wg.Done()
+				err := client.Stop(repGuid, models.StopLRPInstance{
 					ProcessGuid:  stopAuctionInfo.ProcessGuid,
 					InstanceGuid: instanceGuid,
 					Index:        stopAuctionInfo.Index,
 				})
-				wg.Done()
+				if err != nil {
+					lock.Lock()
+					stopFailures = append(stopFailures, auctiontypes.RepFailure{
+						Rep:          repGuid,
+						InstanceGuid: instanceGuid,
+						Error:        err.Error(),
+					})
+					lock.Unlock()
+				}
 			}(stopAuctionBid.Rep, instanceGuid)
 		}
 	}
 	wg.Wait()
 
-	return repGuidWithLoneRemainingInstance, numCommunication, nil
+	return repGuidWithLoneRemainingInstance, stopFailures, numCommunication, nil
 }
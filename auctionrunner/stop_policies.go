@@ -0,0 +1,170 @@
+package auctionrunner
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+/*
+
+A StopPolicy decides which instance of a process index survives a stop auction.
+It is given the bids of the reps running the index (none failed, in random order,
+at least two instances between them) and the number of the process' instances
+in each zone, and returns the rep that keeps the first of its InstanceGuids.
+Every other instance of the index is stopped.
+
+The reps' stop bids already say how good a place each rep is to keep the instance
+(see auctionrep.StopBidScorer): policies weigh those against what only the
+auctioneer can see, the zones and start times across reps.
+
+*/
+
+type StopPolicy func(zones auctiontypes.InstancesByZone, stopAuctionBids auctiontypes.StopAuctionBids) string
+
+type UnknownStopPolicyError struct {
+	Policy string
+}
+
+func (e UnknownStopPolicyError) Error() string {
+	return fmt.Sprintf("unknown stop policy %q", e.Policy)
+}
+
+// DefaultStopPolicy is used by stop auctions that do not name a policy
+const DefaultStopPolicy = "under_represented_zone"
+
+var stopPoliciesLock = &sync.RWMutex{}
+var stopPolicies = map[string]StopPolicy{}
+
+func init() {
+	RegisterStopPolicy("under_represented_zone", underRepresentedZoneStopPolicy)
+	RegisterStopPolicy("least_loaded", leastLoadedStopPolicy)
+	RegisterStopPolicy("oldest", oldestStopPolicy)
+}
+
+// RegisterStopPolicy makes a policy available under name to
+// StopAuctionRequest.Policy. It panics if name is already registered.
+func RegisterStopPolicy(name string, policy StopPolicy) {
+	stopPoliciesLock.Lock()
+	defer stopPoliciesLock.Unlock()
+
+	if policy == nil {
+		panic("auctionrunner: nil stop policy " + name)
+	}
+
+	if _, exists := stopPolicies[name]; exists {
+		panic("auctionrunner: stop policy registered twice " + name)
+	}
+
+	stopPolicies[name] = policy
+}
+
+// StopPolicies returns the sorted names of the registered stop policies.
+func StopPolicies() []string {
+	stopPoliciesLock.RLock()
+	defer stopPoliciesLock.RUnlock()
+
+	names := []string{}
+	for name := range stopPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func lookupStopPolicy(name string) (StopPolicy, error) {
+	if name == "" {
+		name = DefaultStopPolicy
+	}
+
+	stopPoliciesLock.RLock()
+	defer stopPoliciesLock.RUnlock()
+
+	policy, ok := stopPolicies[name]
+	if !ok {
+		return nil, UnknownStopPolicyError{Policy: name}
+	}
+
+	return policy, nil
+}
+
+// keep the instance in the zone running the fewest of the process' other instances, on the rep there bidding lowest
+func underRepresentedZoneStopPolicy(zones auctiontypes.InstancesByZone, stopAuctionBids auctiontypes.StopAuctionBids) string {
+	survivorZones := zonesWithFewestOtherInstances(zones, stopAuctionBids)
+	if len(survivorZones) == 0 {
+		return lowestStopBid(stopAuctionBids)
+	}
+
+	candidates := auctiontypes.StopAuctionBids{}
+	for _, stopAuctionBid := range stopAuctionBids {
+		if survivorZones[stopAuctionBid.Zone] {
+			candidates = append(candidates, stopAuctionBid)
+		}
+	}
+
+	return lowestStopBid(candidates)
+}
+
+// keep the instance on the rep bidding lowest, wherever it is
+func leastLoadedStopPolicy(zones auctiontypes.InstancesByZone, stopAuctionBids auctiontypes.StopAuctionBids) string {
+	return lowestStopBid(stopAuctionBids)
+}
+
+// keep the instance that has been running longest; if no rep knows, keep the one on the rep bidding lowest
+func oldestStopPolicy(zones auctiontypes.InstancesByZone, stopAuctionBids auctiontypes.StopAuctionBids) string {
+	survivor := ""
+	oldest := time.Time{}
+	for _, stopAuctionBid := range stopAuctionBids {
+		//the reps that know list their oldest instance first, so it is the one the survivor keeps
+		for _, startedAt := range stopAuctionBid.InstanceStartTimes {
+			if survivor == "" || startedAt.Before(oldest) {
+				survivor = stopAuctionBid.Rep
+				oldest = startedAt
+			}
+		}
+	}
+
+	if survivor == "" {
+		return lowestStopBid(stopAuctionBids)
+	}
+	return survivor
+}
+
+func lowestStopBid(stopAuctionBids auctiontypes.StopAuctionBids) string {
+	survivor := ""
+	lowest := 0.0
+	for _, stopAuctionBid := range stopAuctionBids {
+		if survivor == "" || stopAuctionBid.Bid < lowest {
+			survivor = stopAuctionBid.Rep
+			lowest = stopAuctionBid.Bid
+		}
+	}
+
+	return survivor
+}
+
+// the survivor should land in the zone running the fewest of the process' other instances
+func zonesWithFewestOtherInstances(zones auctiontypes.InstancesByZone, stopAuctionBids auctiontypes.StopAuctionBids) map[string]bool {
+	otherInstances := auctiontypes.InstancesByZone{}
+	for _, stopAuctionBid := range stopAuctionBids {
+		if stopAuctionBid.Zone != "" {
+			otherInstances[stopAuctionBid.Zone] = zones[stopAuctionBid.Zone]
+		}
+	}
+	for _, stopAuctionBid := range stopAuctionBids {
+		if stopAuctionBid.Zone != "" {
+			otherInstances[stopAuctionBid.Zone] -= len(stopAuctionBid.InstanceGuids)
+		}
+	}
+
+	survivorZones := map[string]bool{}
+	for _, zone := range otherInstances.LeastRepresented() {
+		survivorZones[zone] = true
+	}
+
+	return survivorZones
+}
@@ -0,0 +1,39 @@
+package auctionrunner
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+/*
+
+Spreading (the default) places each instance on the emptiest rep that will take it,
+so a sample of the reps is as good as asking them all.
+
+Packing places each instance on the fullest rep that still has room, so that the
+emptiest reps stay idle and can be drained. The fullest reps are few and a random
+sample would mostly miss them: every rep is asked to bid.
+
+*/
+
+type UnknownStrategyError struct {
+	Strategy string
+}
+
+func (e UnknownStrategyError) Error() string {
+	return fmt.Sprintf("unknown strategy %q", e.Strategy)
+}
+
+// withStrategy adjusts the rules to the placement strategy they ask for
+func withStrategy(rules auctiontypes.StartAuctionRules) (auctiontypes.StartAuctionRules, error) {
+	switch rules.Strategy {
+	case "", auctiontypes.SpreadStrategy:
+		return rules, nil
+	case auctiontypes.PackStrategy:
+		rules.MaxBiddingPoolFraction = 1
+		return rules, nil
+	default:
+		return rules, UnknownStrategyError{Strategy: rules.Strategy}
+	}
+}
@@ -0,0 +1,36 @@
+package auctiontypes
+
+import "github.com/cloudfoundry-incubator/auction/util"
+
+func (v BatchStartAuctionBids) FilterErrors() BatchStartAuctionBids {
+	out := BatchStartAuctionBids{}
+	for _, r := range v {
+		if r.Error == "" {
+			out = append(out, r)
+		}
+	}
+
+	return out
+}
+
+func (v BatchStartAuctionBids) Shuffle() BatchStartAuctionBids {
+	out := make(BatchStartAuctionBids, len(v))
+
+	perm := util.R.Perm(len(v))
+	for i, index := range perm {
+		out[i] = v[index]
+	}
+
+	return out
+}
+
+func (v StartAuctionReservations) FilterErrors() StartAuctionReservations {
+	out := StartAuctionReservations{}
+	for _, r := range v {
+		if r.Error == "" {
+			out = append(out, r)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,37 @@
+package auctiontypes
+
+import "sort"
+
+// Less prefers the bid from the zone running fewer instances of the process, then the lower bid
+func (z InstancesByZone) Less(a StartAuctionBid, b StartAuctionBid) bool {
+	if z[a.Zone] != z[b.Zone] {
+		return z[a.Zone] < z[b.Zone]
+	}
+	return a.Bid < b.Bid
+}
+
+// LeastRepresented returns the zones running the fewest instances of the process
+func (z InstancesByZone) LeastRepresented() []string {
+	zones := []string{}
+	for zone, n := range z {
+		if len(zones) == 0 || n < z[zones[0]] {
+			zones = []string{zone}
+		} else if n == z[zones[0]] {
+			zones = append(zones, zone)
+		}
+	}
+
+	sort.Strings(zones)
+	return zones
+}
+
+type zoneBalancedStartAuctionBids struct {
+	bids            StartAuctionBids
+	instancesByZone InstancesByZone
+}
+
+func (a zoneBalancedStartAuctionBids) Len() int      { return len(a.bids) }
+func (a zoneBalancedStartAuctionBids) Swap(i, j int) { a.bids[i], a.bids[j] = a.bids[j], a.bids[i] }
+func (a zoneBalancedStartAuctionBids) Less(i, j int) bool {
+	return a.instancesByZone.Less(a.bids[i], a.bids[j])
+}
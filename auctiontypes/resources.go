@@ -0,0 +1,168 @@
+package auctiontypes
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// InstanceResources is what one instance with the given memory and disk takes: those, a container and any further resources it needs
+func InstanceResources(memoryMB int, diskMB int, further Resources) Resources {
+	demand := Resources{}
+	demand.addInstance(memoryMB, diskMB, further)
+	return demand
+}
+
+func (info StartAuctionInfo) Demand() Resources {
+	return InstanceResources(info.MemoryMB, info.DiskMB, info.Resources)
+}
+
+func (instance EvictableInstance) Demand() Resources {
+	return InstanceResources(instance.MemoryMB, instance.DiskMB, instance.Resources)
+}
+
+func (instance SimulatedInstance) Demand() Resources {
+	return InstanceResources(instance.MemoryMB, instance.DiskMB, instance.Resources)
+}
+
+// TotalDemand is what the instances take between them
+func TotalDemand(instances []SimulatedInstance) Resources {
+	total := Resources{}
+	for _, instance := range instances {
+		total.addInstance(instance.MemoryMB, instance.DiskMB, instance.Resources)
+	}
+	return total
+}
+
+func (r Resources) Add(other Resources) Resources {
+	sum := r.copy()
+	for name, quantity := range other {
+		sum[name] += quantity
+	}
+	return sum
+}
+
+func (r Resources) Subtract(other Resources) Resources {
+	difference := r.copy()
+	for name, quantity := range other {
+		difference[name] -= quantity
+	}
+	return difference
+}
+
+// Covers reports whether there is at least as much of every resource as the demand asks for
+func (r Resources) Covers(demand Resources) bool {
+	for name, quantity := range demand {
+		if r[name] < quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// Usage is the fraction of each resource in use, given the total there is of them; resources there are none of are left out
+func (r Resources) Usage(total Resources) map[string]float64 {
+	usage := map[string]float64{}
+	for name, quantity := range total {
+		if quantity > 0 {
+			usage[name] = 1.0 - float64(r[name])/float64(quantity)
+		}
+	}
+	return usage
+}
+
+// Names returns the sorted names of the resources in the vector
+func (r Resources) Names() []string {
+	names := []string{}
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// in place: only for vectors nobody else holds yet
+func (r Resources) addInstance(memoryMB int, diskMB int, further Resources) {
+	r[MemoryMB] += memoryMB
+	r[DiskMB] += diskMB
+	r[Containers] += 1
+	for name, quantity := range further {
+		r[name] += quantity
+	}
+}
+
+func (r Resources) copy() Resources {
+	c := Resources{}
+	for name, quantity := range r {
+		c[name] = quantity
+	}
+	return c
+}
+
+// Total is what a rep with the given resources may promise
+func (f OvercommitFactors) Total(total Resources) Resources {
+	overcommitted := total.copy()
+	for name, factor := range f {
+		if quantity, ok := total[name]; ok {
+			overcommitted[name] = int(math.Floor(float64(quantity) * factor))
+		}
+	}
+	return overcommitted
+}
+
+// Remaining is what a rep with the given resources may still promise: the overcommitted total less what is in use
+func (f OvercommitFactors) Remaining(remaining Resources, total Resources) Resources {
+	return remaining.Add(f.Total(total).Subtract(total))
+}
+
+// before Resources was a vector it was a struct of these fields: they keep their keys on the wire
+// so that reps and auctioneers on either side of the change understand each other
+var legacyResourceKeys = map[string]string{
+	MemoryMB:   "MemoryMB",
+	DiskMB:     "DiskMB",
+	Containers: "Containers",
+}
+
+func (r Resources) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+
+	keyed := map[string]int{}
+	for name, quantity := range r {
+		if key, ok := legacyResourceKeys[name]; ok {
+			name = key
+		}
+		keyed[name] = quantity
+	}
+
+	return json.Marshal(keyed)
+}
+
+func (r *Resources) UnmarshalJSON(payload []byte) error {
+	keyed := map[string]int{}
+	err := json.Unmarshal(payload, &keyed)
+	if err != nil {
+		return err
+	}
+
+	if keyed == nil {
+		*r = nil
+		return nil
+	}
+
+	resources := Resources{}
+	for key, quantity := range keyed {
+		resources[key] = quantity
+	}
+	for name, key := range legacyResourceKeys {
+		if quantity, ok := keyed[key]; ok {
+			delete(resources, key)
+			resources[name] = quantity
+		}
+	}
+
+	*r = resources
+	return nil
+}
@@ -49,3 +49,31 @@ func (v StartAuctionBids) Sort() StartAuctionBids {
 	sort.Sort(v)
 	return v
 }
+
+func (v StartAuctionBids) InstancesByZone() InstancesByZone {
+	out := InstancesByZone{}
+	for _, r := range v {
+		if r.Zone != "" {
+			out[r.Zone] += r.NumInstancesForProcessGuid
+		}
+	}
+
+	return out
+}
+
+func (v StartAuctionBids) SortBalancingZones(instancesByZone InstancesByZone) StartAuctionBids {
+	sort.Sort(zoneBalancedStartAuctionBids{bids: v, instancesByZone: instancesByZone})
+	return v
+}
+
+// Ranked orders the bids the way the algorithms pick winners (best first) and puts the failed bids last
+func (v StartAuctionBids) Ranked() StartAuctionBids {
+	ranked := v.FilterErrors().Shuffle().SortBalancingZones(v.InstancesByZone())
+	for _, r := range v {
+		if r.Error != "" {
+			ranked = append(ranked, r)
+		}
+	}
+
+	return ranked
+}
@@ -58,3 +58,14 @@ func (v StopAuctionBids) Sort() StopAuctionBids {
 	sort.Sort(v)
 	return v
 }
+
+func (v StopAuctionBids) InstancesByZone() InstancesByZone {
+	out := InstancesByZone{}
+	for _, r := range v {
+		if r.Zone != "" {
+			out[r.Zone] += r.NumInstancesForProcessGuid
+		}
+	}
+
+	return out
+}
@@ -1,6 +1,7 @@
 package auctiontypes
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -9,37 +10,127 @@ import (
 
 //errors
 var InsufficientResources = errors.New("insufficient resources for instance")
+var ConstraintNotSatisfied = errors.New("rep does not satisfy the instance's placement constraints")
+var DuplicateInstance = errors.New("rep already holds the instance")
+var AuctionInProgress = errors.New("the instance is already being auctioned")
 var NothingToStop = errors.New("found nothing to stop")
+var AuctionDeadlineExceeded = errors.New("auction deadline exceeded")
+var AuctionCancelled = errors.New("auction cancelled")
+var RepDraining = errors.New("rep is draining")
+var RepBusy = errors.New("rep is too busy to take on the instance now")
+var EvacuationIncomplete = errors.New("some instances could not be evacuated")
 
 //AuctionRunner
 type AuctionRunner interface {
 	RunLRPStartAuction(auctionRequest StartAuctionRequest) (StartAuctionResult, error)
 	RunLRPStopAuction(auctionRequest StopAuctionRequest) (StopAuctionResult, error)
+	RunLRPStartAuctionBatch(auctionRequest StartAuctionBatchRequest) ([]StartAuctionResult, error)
+
+	RunLRPStartAuctionWithContext(ctx context.Context, auctionRequest StartAuctionRequest) (StartAuctionResult, error)
+	RunLRPStopAuctionWithContext(ctx context.Context, auctionRequest StopAuctionRequest) (StopAuctionResult, error)
+	RunLRPStartAuctionBatchWithContext(ctx context.Context, auctionRequest StartAuctionBatchRequest) ([]StartAuctionResult, error)
+
+	RunEvacuation(evacuationRequest EvacuationRequest) (EvacuationResult, error)
+	RunEvacuationWithContext(ctx context.Context, evacuationRequest EvacuationRequest) (EvacuationResult, error)
 }
 
 type StartAuctionRequest struct {
-	LRPStartAuction models.LRPStartAuction
-	RepGuids        RepGuids
-	Rules           StartAuctionRules
+	LRPStartAuction    models.LRPStartAuction
+	RepGuids           RepGuids
+	Rules              StartAuctionRules
+	RequiredAttributes []string
+	AffinityRules      []AffinityRule
+	Priority           int
+	DryRun             bool
+	//what the instance needs beyond the memory and disk of its LRPStartAuction, e.g. cpu_millis or ports
+	Resources Resources `json:",omitempty"`
 }
 
 type StartAuctionResult struct {
 	LRPStartAuction   models.LRPStartAuction
+	TraceID           string `json:",omitempty"`
 	Winner            string
+	RankedBids        StartAuctionBids
+	Evicted           []models.StopLRPInstance
 	NumRounds         int
 	NumCommunications int
 	BiddingDuration   time.Duration
 	Duration          time.Duration
+	RunFailures       []RepFailure        `json:",omitempty"`
+	History           []StartAuctionRound `json:",omitempty"`
+	//why the winner won, if the rules asked the reps to explain their bids
+	Explanation *BidExplanation `json:",omitempty"`
+	//why a batched instance went unplaced; single auctions return their error instead
+	Error string `json:",omitempty"`
+}
+
+// RepFailure records a rep that failed to run or stop an instance it was told to
+type RepFailure struct {
+	Rep          string
+	InstanceGuid string
+	Error        string
+}
+
+// StartAuctionRound records one round of a start auction (see StartAuctionRules.RecordHistory)
+type StartAuctionRound struct {
+	Round     int
+	Sampled   []string
+	Bids      StartAuctionBids
+	Reserved  []string
+	Released  []string
+	Ran       string
+	Abandoned string
+}
+
+type StartAuctionBatchRequest struct {
+	LRPStartAuctions                []models.LRPStartAuction
+	RepGuids                        RepGuids
+	Rules                           StartAuctionRules
+	RequiredAttributesByProcessGuid map[string][]string
+	AffinityRulesByProcessGuid      map[string][]AffinityRule
+	ResourcesByProcessGuid          map[string]Resources `json:",omitempty"`
+}
+
+// EvacuationRequest takes a rep out of service: the rep is set draining and
+// each of LRPStartAuctions, the instances running on it, is auctioned among RepGuids and only then stopped on the rep
+type EvacuationRequest struct {
+	RepGuid                         string
+	LRPStartAuctions                []models.LRPStartAuction
+	RepGuids                        RepGuids
+	Rules                           StartAuctionRules
+	RequiredAttributesByProcessGuid map[string][]string
+	AffinityRulesByProcessGuid      map[string][]AffinityRule
+	ResourcesByProcessGuid          map[string]Resources `json:",omitempty"`
+}
+
+type EvacuationResult struct {
+	RepGuid   string
+	Instances []InstanceEvacuation
+	Duration  time.Duration
+}
+
+// InstanceEvacuation is how the move of one instance off a draining rep went:
+// Winner runs it now ("" if nobody would) and Stopped says whether the draining rep stopped its copy.
+// The instance's start auction and the stop of its copy are traced under TraceID.
+type InstanceEvacuation struct {
+	LRPStartAuction models.LRPStartAuction
+	Winner          string
+	Stopped         bool
+	Error           string `json:",omitempty"`
+	TraceID         string `json:",omitempty"`
 }
 
 type StopAuctionRequest struct {
 	LRPStopAuction models.LRPStopAuction
 	RepGuids       RepGuids
+	//how the runner picks the instance to keep, one of auctionrunner.StopPolicies(); empty for the default
+	Policy string `json:",omitempty"`
 }
 
 type StopAuctionResult struct {
 	LRPStopAuction    models.LRPStopAuction
 	Winner            string
+	StopFailures      []RepFailure `json:",omitempty"`
 	NumCommunications int
 	BiddingDuration   time.Duration
 	Duration          time.Duration
@@ -50,24 +141,53 @@ type StartAuctionRules struct {
 	MaxRounds              int
 	MaxBiddingPoolFraction float64
 	MinBiddingPool         int
+	NumChoices             int
+	MaxDuration            time.Duration
+	Preemption             bool
+	RecordHistory          bool
+	Strategy               string `json:",omitempty"`
+	//ask the reps to explain their bids (see StartAuctionBid.Explanation)
+	ExplainBids bool `json:",omitempty"`
 }
 
+// the placement strategies: spread instances over the emptiest reps (the default) or pack them onto the fullest
+const (
+	SpreadStrategy = "spread"
+	PackStrategy   = "pack"
+)
+
 type RepGuids []string
 
 type RepPoolClient interface {
 	BidForStartAuction(repGuids []string, startAuctionInfo StartAuctionInfo) StartAuctionBids
 	BidForStopAuction(repGuids []string, stopAuctionInfo StopAuctionInfo) StopAuctionBids
 	RebidThenTentativelyReserve(repGuids []string, startAuctionInfo StartAuctionInfo) StartAuctionBids
+	BidForBatchStartAuction(repGuids []string, startAuctionInfos []StartAuctionInfo) BatchStartAuctionBids
+	TentativelyReserveBatch(startAuctionInfosByRepGuid map[string][]StartAuctionInfo) StartAuctionReservations
 	ReleaseReservation(repGuids []string, startAuctionInfo StartAuctionInfo)
-	Run(repGuid string, startAuctionInfo models.LRPStartAuction)
-	Stop(repGuid string, stopInstance models.StopLRPInstance)
+	Run(repGuid string, startAuctionInfo models.LRPStartAuction) error
+	Stop(repGuid string, stopInstance models.StopLRPInstance) error
+	SetDraining(repGuid string, draining bool) error
+}
+
+// TracingRepPoolClient is implemented by RepPoolClients that can tag every request they send on behalf of one auction,
+// so the auction can be followed through the logs of every rep it touched
+type TracingRepPoolClient interface {
+	RepPoolClient
+	WithTraceID(traceID string) RepPoolClient
 }
 
 type AuctionRepDelegate interface {
+	Zone() (string, error)
+	Attributes() ([]string, error)
+	ProcessGuids() ([]string, error)
+	//the running instances below the given priority; reserved ones belong to another auction and are never evicted
+	EvictableInstances(priority int) ([]EvictableInstance, error)
 	RemainingResources() (Resources, error)
 	TotalResources() (Resources, error)
 	NumInstancesForProcessGuid(processGuid string) (int, error)
 	InstanceGuidsForProcessGuidAndIndex(processGuid string, index int) ([]string, error)
+	Reservations() ([]Reservation, error)
 
 	Reserve(startAuctionInfo StartAuctionInfo) error
 	ReleaseReservation(startAuctionInfo StartAuctionInfo) error
@@ -75,13 +195,22 @@ type AuctionRepDelegate interface {
 	Stop(stopInstance models.StopLRPInstance) error
 }
 
+// InstanceStartTimesAuctionRepDelegate is implemented by delegates that know when their instances started,
+// which lets reps and auctioneers keep the oldest instance when stopping duplicates
+type InstanceStartTimesAuctionRepDelegate interface {
+	AuctionRepDelegate
+	InstanceStartTimes(instanceGuids []string) (map[string]time.Time, error)
+}
+
 //simulation-only interface
 type SimulationRepPoolClient interface {
 	RepPoolClient
 
 	TotalResources(repGuid string) Resources
+	Zone(repGuid string) string
 	SimulatedInstances(repGuid string) []SimulatedInstance
 	SetSimulatedInstances(repGuid string, instances []SimulatedInstance)
+	SetFailsToRun(repGuid string, failsToRun bool)
 	Reset(repGuid string)
 }
 
@@ -90,16 +219,39 @@ type SimulationAuctionRepDelegate interface {
 	AuctionRepDelegate
 	SetSimulatedInstances(instances []SimulatedInstance)
 	SimulatedInstances() []SimulatedInstance
+	SetFailsToRun(failsToRun bool)
 }
 
 func NewStartAuctionInfoFromLRPStartAuction(auction models.LRPStartAuction) StartAuctionInfo {
-	return StartAuctionInfo{
+	info := StartAuctionInfo{
 		ProcessGuid:  auction.ProcessGuid,
 		InstanceGuid: auction.InstanceGuid,
 		DiskMB:       auction.DiskMB,
 		MemoryMB:     auction.MemoryMB,
 		Index:        auction.Index,
 	}
+
+	if auction.Stack != "" {
+		info.RequiredAttributes = []string{StackAttribute(auction.Stack)}
+	}
+
+	return info
+}
+
+func NewStartAuctionInfoFromStartAuctionRequest(auctionRequest StartAuctionRequest) StartAuctionInfo {
+	info := NewStartAuctionInfoFromLRPStartAuction(auctionRequest.LRPStartAuction)
+	info.RequiredAttributes = append(info.RequiredAttributes, auctionRequest.RequiredAttributes...)
+	info.AffinityRules = auctionRequest.AffinityRules
+	info.Priority = auctionRequest.Priority
+	info.Strategy = auctionRequest.Rules.Strategy
+	info.Resources = auctionRequest.Resources
+	info.Explain = auctionRequest.Rules.ExplainBids
+	return info
+}
+
+// the attribute a rep advertises to run instances on the given stack
+func StackAttribute(stack string) string {
+	return "stack=" + stack
 }
 
 func NewStopAuctionInfoFromLRPStopAuction(auction models.LRPStopAuction) StopAuctionInfo {
@@ -110,34 +262,140 @@ func NewStopAuctionInfoFromLRPStopAuction(auction models.LRPStopAuction) StopAuc
 }
 
 type StartAuctionBid struct {
-	Rep   string
-	Bid   float64
-	Error string
+	Rep                        string
+	Zone                       string
+	NumInstancesForProcessGuid int
+	Bid                        float64
+	Victims                    []EvictableInstance
+	Error                      string
+	Explanation                *BidExplanation `json:",omitempty"`
 }
 
 type StartAuctionBids []StartAuctionBid
 
+// BidExplanation is what a rep made of its state when it bid (or refused to), returned only to auctions that ask for it.
+// A bid is Score plus AffinityPenalty plus EvictionCost.
+type BidExplanation struct {
+	RemainingResources         Resources
+	TotalResources             Resources
+	NumInstancesForProcessGuid int
+	//the fraction of each resource the rep has in use
+	Usage           map[string]float64
+	Constraints     []ConstraintResult
+	Score           float64
+	AffinityPenalty float64
+	EvictionCost    float64
+}
+
+// ConstraintResult says whether a rep satisfied one of the constraints on an instance:
+// a required attribute, a hard affinity rule or room for one of the resources it needs
+type ConstraintResult struct {
+	Constraint string
+	Satisfied  bool
+}
+
+type BatchStartAuctionBid struct {
+	Rep                         string
+	Zone                        string
+	Attributes                  []string
+	ProcessGuids                []string
+	RemainingResources          Resources
+	TotalResources              Resources
+	NumInstancesForProcessGuids map[string]int
+	Error                       string
+	//what the rep's own scorer makes of its snapshot for each instance, by InstanceGuid, before affinity penalties
+	Scores map[string]float64 `json:",omitempty"`
+}
+
+type BatchStartAuctionBids []BatchStartAuctionBid
+
+type StartAuctionReservation struct {
+	Rep          string
+	InstanceGuid string
+	Error        string
+}
+
+type StartAuctionReservations []StartAuctionReservation
+
 type StopAuctionBid struct {
-	Rep           string
-	InstanceGuids []string
-	Bid           float64
-	Error         string
+	Rep                        string
+	Zone                       string
+	NumInstancesForProcessGuid int
+	InstanceGuids              []string
+	Bid                        float64
+	Error                      string
+	//when the instances started, for reps that know
+	InstanceStartTimes map[string]time.Time `json:",omitempty"`
 }
 
 type StopAuctionBids []StopAuctionBid
 
-type Resources struct {
-	DiskMB     int
-	MemoryMB   int
-	Containers int
-}
+// InstancesByZone counts the instances of a process running in each zone
+type InstancesByZone map[string]int
+
+// Resources is a vector of quantities by resource name; a name it lacks counts as none.
+// Treat it as a value: use its methods to derive new vectors rather than writing to one that may be shared.
+type Resources map[string]int
+
+// OvercommitFactors scale what a rep has of each resource, e.g. 1.5 to promise half again as much memory as it has;
+// resources without a factor are not overcommitted
+type OvercommitFactors map[string]float64
+
+// the resources every rep has and every instance takes (one container each); reps may advertise any others
+const (
+	MemoryMB   = "memory_mb"
+	DiskMB     = "disk_mb"
+	Containers = "containers"
+	CPUMillis  = "cpu_millis"
+	Ports      = "ports"
+)
 
 type StartAuctionInfo struct {
+	ProcessGuid        string
+	InstanceGuid       string
+	DiskMB             int
+	MemoryMB           int
+	Index              int
+	RequiredAttributes []string
+	AffinityRules      []AffinityRule
+	Priority           int
+	Preempt            bool
+	Strategy           string    `json:",omitempty"`
+	Resources          Resources `json:",omitempty"`
+	Explain            bool      `json:",omitempty"`
+}
+
+// EvictableInstance is a running instance a rep could stop to make room for a higher-priority one
+type EvictableInstance struct {
 	ProcessGuid  string
 	InstanceGuid string
-	DiskMB       int
-	MemoryMB     int
 	Index        int
+	Priority     int
+	MemoryMB     int
+	DiskMB       int
+	Resources    Resources `json:",omitempty"`
+}
+
+func (instance EvictableInstance) StopLRPInstance() models.StopLRPInstance {
+	return models.StopLRPInstance{
+		ProcessGuid:  instance.ProcessGuid,
+		InstanceGuid: instance.InstanceGuid,
+		Index:        instance.Index,
+	}
+}
+
+// Reservation is room a rep has set aside for an instance it has not been told to run yet
+type Reservation struct {
+	StartAuctionInfo StartAuctionInfo
+	ReservedAt       time.Time
+}
+
+// AffinityRule places an instance with (or, if Anti, away from) the instances of another process.
+// Hard rules are constraints reps refuse to break, soft rules only make breaking them a worse bid.
+type AffinityRule struct {
+	ProcessGuid string
+	Anti        bool
+	Hard        bool
 }
 
 func (info StartAuctionInfo) LRPIdentifier() models.LRPIdentifier {
@@ -159,4 +417,8 @@ type SimulatedInstance struct {
 	Index        int
 	MemoryMB     int
 	DiskMB       int
+	Resources    Resources `json:",omitempty"`
+	Priority     int
+	Reserved     bool
+	StartedAt    time.Time
 }
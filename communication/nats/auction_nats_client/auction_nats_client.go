@@ -3,6 +3,7 @@ package auction_nats_client
 import (
 	"encoding/json"
 	"errors"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +22,7 @@ type AuctionNATSClient struct {
 	timeout    time.Duration
 	runTimeout time.Duration
 	logger     lager.Logger
+	traceID    string
 }
 
 func New(natsClient yagnats.NATSClient, timeout time.Duration, runTimeout time.Duration, logger lager.Logger) (*AuctionNATSClient, error) {
@@ -38,6 +40,16 @@ func New(natsClient yagnats.NATSClient, timeout time.Duration, runTimeout time.D
 	}, nil
 }
 
+// WithTraceID returns a client that logs and sends every request under the given trace ID
+func (rep *AuctionNATSClient) WithTraceID(traceID string) auctiontypes.RepPoolClient {
+	traced := *rep
+	traced.traceID = traceID
+	traced.logger = rep.logger.Session("auction", lager.Data{
+		"trace-id": traceID,
+	})
+	return &traced
+}
+
 func (rep *AuctionNATSClient) BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
 	bidLog := rep.logger.Session("start-bid", lager.Data{
 		"start-auction-info": startAuctionInfo,
@@ -158,6 +170,87 @@ func (rep *AuctionNATSClient) RebidThenTentativelyReserve(repGuids []string, sta
 	return results
 }
 
+func (rep *AuctionNATSClient) BidForBatchStartAuction(repGuids []string, startAuctionInfos []auctiontypes.StartAuctionInfo) auctiontypes.BatchStartAuctionBids {
+	bidLog := rep.logger.Session("batch-start-bid", lager.Data{
+		"num-start-auction-infos": len(startAuctionInfos),
+		"num-rep-guids":           len(repGuids),
+	})
+
+	bidLog.Info("fetching")
+
+	subjects := []string{}
+	for _, repGuid := range repGuids {
+		subjects = append(subjects, nats.NewSubjects(repGuid).BidForBatchStartAuction)
+	}
+	payload, _ := json.Marshal(startAuctionInfos)
+
+	responses, _ := rep.aggregateWithTimeout(bidLog, subjects, payload, rep.timeout)
+
+	results := auctiontypes.BatchStartAuctionBids{}
+	for _, response := range responses {
+		bid := auctiontypes.BatchStartAuctionBid{}
+		err := json.Unmarshal(response, &bid)
+		if err != nil {
+			bidLog.Error("failed-to-unmarshal", err, lager.Data{
+				"payload": string(response),
+			})
+			continue
+		}
+		results = append(results, bid)
+	}
+
+	bidLog.Info("fetched", lager.Data{
+		"num-bids-received": len(results),
+	})
+
+	return results
+}
+
+func (rep *AuctionNATSClient) TentativelyReserveBatch(startAuctionInfosByRepGuid map[string][]auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionReservations {
+	reserveLog := rep.logger.Session("reserve-batch", lager.Data{
+		"num-rep-guids": len(startAuctionInfosByRepGuid),
+	})
+
+	reserveLog.Info("reserving")
+
+	requests := []natsRequest{}
+	subjectToRepGuid := map[string]string{}
+	for repGuid, startAuctionInfos := range startAuctionInfosByRepGuid {
+		subject := nats.NewSubjects(repGuid).TentativelyReserveBatch
+		payload, _ := json.Marshal(startAuctionInfos)
+		requests = append(requests, natsRequest{subject: subject, payload: payload})
+		subjectToRepGuid[subject] = repGuid
+	}
+
+	responses, failedSubjects := rep.aggregateRequestsWithTimeout(reserveLog, requests, rep.timeout)
+
+	results := auctiontypes.StartAuctionReservations{}
+	for _, response := range responses {
+		reservations := auctiontypes.StartAuctionReservations{}
+		err := json.Unmarshal(response, &reservations)
+		if err != nil {
+			reserveLog.Error("failed-to-unmarshal", err, lager.Data{
+				"payload": string(response),
+			})
+			continue
+		}
+		results = append(results, reservations...)
+	}
+
+	for _, failedSubject := range failedSubjects {
+		repGuid := subjectToRepGuid[failedSubject]
+		for _, startAuctionInfo := range startAuctionInfosByRepGuid[repGuid] {
+			rep.ReleaseReservation([]string{repGuid}, startAuctionInfo)
+		}
+	}
+
+	reserveLog.Info("reserved", lager.Data{
+		"num-reservations-received": len(results),
+	})
+
+	return results
+}
+
 func (rep *AuctionNATSClient) ReleaseReservation(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) {
 	releaseLog := rep.logger.Session("release-reservation", lager.Data{
 		"start-auction-info":   startAuctionInfo,
@@ -178,7 +271,7 @@ func (rep *AuctionNATSClient) ReleaseReservation(repGuids []string, startAuction
 	releaseLog.Info("done")
 }
 
-func (rep *AuctionNATSClient) Run(repGuid string, startAuction models.LRPStartAuction) {
+func (rep *AuctionNATSClient) Run(repGuid string, startAuction models.LRPStartAuction) error {
 	runLog := rep.logger.Session("run", lager.Data{
 		"start-auction-info": startAuction,
 		"rep-guid":           repGuid,
@@ -192,13 +285,14 @@ func (rep *AuctionNATSClient) Run(repGuid string, startAuction models.LRPStartAu
 
 	if err != nil {
 		runLog.Error("failed-to-publish", err)
-		return
+		return err
 	}
 
 	runLog.Info("done")
+	return nil
 }
 
-func (rep *AuctionNATSClient) Stop(repGuid string, stopInstance models.StopLRPInstance) {
+func (rep *AuctionNATSClient) Stop(repGuid string, stopInstance models.StopLRPInstance) error {
 	stopLog := rep.logger.Session("stop", lager.Data{
 		"stop-instance": stopInstance,
 		"rep-guid":      repGuid,
@@ -213,14 +307,42 @@ func (rep *AuctionNATSClient) Stop(repGuid string, stopInstance models.StopLRPIn
 
 	if err != nil {
 		stopLog.Error("failed-to-publish", err)
-		return
+		return err
 	}
 
 	stopLog.Info("done")
+	return nil
+}
+
+func (rep *AuctionNATSClient) SetDraining(repGuid string, draining bool) error {
+	drainLog := rep.logger.Session("set-draining", lager.Data{
+		"draining": draining,
+		"rep-guid": repGuid,
+	})
+
+	drainLog.Info("starting")
+
+	subjects := nats.NewSubjects(repGuid)
+	payload, _ := json.Marshal(draining)
+
+	_, err := rep.publishWithTimeout(subjects.SetDraining, payload, rep.timeout)
+
+	if err != nil {
+		drainLog.Error("failed-to-publish", err)
+		return err
+	}
+
+	drainLog.Info("done")
+	return nil
 }
 
-func (rep *AuctionNATSClient) publishWithTimeout(subject string, payload []byte, timeout time.Duration) ([]byte, error) {
-	response, err := rep.client.Request(subject, payload, timeout)
+func (rep *AuctionNATSClient) publishWithTimeout(subject string, payload []byte, timeout time.Duration) (response []byte, err error) {
+	started := time.Now()
+	defer func() {
+		recordRepRequest(subject, started, err)
+	}()
+
+	response, err = rep.client.RequestWithTraceID(subject, rep.traceID, payload, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -229,27 +351,46 @@ func (rep *AuctionNATSClient) publishWithTimeout(subject string, payload []byte,
 		return nil, RequestFailedError
 	}
 
+	//the rep said why
+	if strings.HasPrefix(string(response), "error: ") {
+		return nil, errors.New(strings.TrimPrefix(string(response), "error: "))
+	}
+
 	return response, nil
 }
 
+type natsRequest struct {
+	subject string
+	payload []byte
+}
+
 func (rep *AuctionNATSClient) aggregateWithTimeout(logger lager.Logger, subjects []string, payload []byte, timeout time.Duration) ([][]byte, []string) {
+	requests := make([]natsRequest, len(subjects))
+	for i, subject := range subjects {
+		requests[i] = natsRequest{subject: subject, payload: payload}
+	}
+
+	return rep.aggregateRequestsWithTimeout(logger, requests, timeout)
+}
+
+func (rep *AuctionNATSClient) aggregateRequestsWithTimeout(logger lager.Logger, requests []natsRequest, timeout time.Duration) ([][]byte, []string) {
 	allReceived := new(sync.WaitGroup)
-	allReceived.Add(len(subjects))
+	allReceived.Add(len(requests))
 
 	lock := &sync.Mutex{}
 	results := [][]byte{}
 	failed := []string{}
 
-	for _, subject := range subjects {
-		go func(subject string) {
+	for _, request := range requests {
+		go func(request natsRequest) {
 			defer allReceived.Done()
 
-			result, err := rep.publishWithTimeout(subject, payload, timeout)
+			result, err := rep.publishWithTimeout(request.subject, request.payload, timeout)
 			if err != nil {
 				logger.Error("aggregate-request-publish-failed", err)
 
 				lock.Lock()
-				failed = append(failed, subject)
+				failed = append(failed, request.subject)
 				lock.Unlock()
 
 				return
@@ -258,7 +399,7 @@ func (rep *AuctionNATSClient) aggregateWithTimeout(logger lager.Logger, subjects
 			lock.Lock()
 			results = append(results, result)
 			lock.Unlock()
-		}(subject)
+		}(request)
 	}
 
 	allReceived.Wait()
@@ -286,6 +427,24 @@ func (rep *AuctionNATSClient) TotalResources(repGuid string) auctiontypes.Resour
 	return totalResources
 }
 
+func (rep *AuctionNATSClient) Zone(repGuid string) string {
+	var zone string
+	subjects := nats.NewSubjects(repGuid)
+	response, err := rep.publishWithTimeout(subjects.Zone, nil, rep.timeout)
+	if err != nil {
+		//test only, so panic is OK
+		panic(err)
+	}
+
+	err = json.Unmarshal(response, &zone)
+	if err != nil {
+		//test only, so panic is OK
+		panic(err)
+	}
+
+	return zone
+}
+
 func (rep *AuctionNATSClient) SimulatedInstances(repGuid string) []auctiontypes.SimulatedInstance {
 	var instances []auctiontypes.SimulatedInstance
 	subjects := nats.NewSubjects(repGuid)
@@ -313,6 +472,16 @@ func (rep *AuctionNATSClient) Reset(repGuid string) {
 	}
 }
 
+func (rep *AuctionNATSClient) SetFailsToRun(repGuid string, failsToRun bool) {
+	subjects := nats.NewSubjects(repGuid)
+	payload, _ := json.Marshal(failsToRun)
+	_, err := rep.publishWithTimeout(subjects.SetFailsToRun, payload, rep.timeout)
+	if err != nil {
+		//test only, so panic is OK
+		panic(err)
+	}
+}
+
 func (rep *AuctionNATSClient) SetSimulatedInstances(repGuid string, instances []auctiontypes.SimulatedInstance) {
 	subjects := nats.NewSubjects(repGuid)
 	payload, _ := json.Marshal(instances)
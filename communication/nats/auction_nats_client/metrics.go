@@ -0,0 +1,33 @@
+package auction_nats_client
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/auction/communication/nats/nats_muxer"
+	"github.com/cloudfoundry-incubator/auction/metrics"
+)
+
+var (
+	repRequestLatency  = metrics.Default.Histogram("auction_rep_request_latency_seconds", "Round-trip time of requests to reps.", []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}, "rep", "request")
+	repRequestTimeouts = metrics.Default.Counter("auction_rep_request_timeouts_total", "Requests to reps that timed out.", "rep", "request")
+	repRequestFailures = metrics.Default.Counter("auction_rep_request_failures_total", "Requests to reps answered with an error.", "rep", "request")
+)
+
+func recordRepRequest(subject string, started time.Time, err error) {
+	//subjects are <rep guid>.<request>
+	rep, request := subject, ""
+	parts := strings.SplitN(subject, ".", 2)
+	if len(parts) == 2 {
+		rep, request = parts[0], parts[1]
+	}
+
+	switch err {
+	case nil:
+		repRequestLatency.Observe(time.Since(started).Seconds(), rep, request)
+	case nats_muxer.TimeoutError:
+		repRequestTimeouts.Inc(rep, request)
+	default:
+		repRequestFailures.Inc(rep, request)
+	}
+}
@@ -1,8 +1,11 @@
 package auction_nats_server
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/auction/auctionrep"
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
@@ -16,6 +19,11 @@ import (
 var errorResponse = []byte("error")
 var successResponse = []byte("ok")
 
+// the auctioneer is told why the rep failed, so it can report it
+func errorResponseFor(err error) []byte {
+	return []byte(string(errorResponse) + ": " + err.Error())
+}
+
 type AuctionNATSServer struct {
 	repGuid string
 	rep     *auctionrep.AuctionRep
@@ -51,9 +59,7 @@ func (s *AuctionNATSServer) Run(sigChan <-chan os.Signal, ready chan<- struct{})
 }
 
 func (s *AuctionNATSServer) start(subjects nats.Subjects) {
-	natsLog := s.logger.Session("nats-handler")
-
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.TotalResources, func(payload []byte) []byte {
+	s.handle(subjects.TotalResources, func(natsLog lager.Logger, payload []byte) []byte {
 		totalResourcesLog := natsLog.Session("total-resources")
 
 		totalResourcesLog.Info("handling")
@@ -61,7 +67,15 @@ func (s *AuctionNATSServer) start(subjects nats.Subjects) {
 		return out
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.BidForStartAuction, func(payload []byte) []byte {
+	s.handle(subjects.Zone, func(natsLog lager.Logger, payload []byte) []byte {
+		zoneLog := natsLog.Session("zone")
+
+		zoneLog.Info("handling")
+		out, _ := json.Marshal(s.rep.Zone())
+		return out
+	})
+
+	s.handle(subjects.BidForStartAuction, func(natsLog lager.Logger, payload []byte) []byte {
 		bidLog := natsLog.Session("bid-for-start")
 
 		bidLog.Info("handling")
@@ -71,25 +85,19 @@ func (s *AuctionNATSServer) start(subjects nats.Subjects) {
 		err := json.Unmarshal(payload, &inst)
 		if err != nil {
 			bidLog.Error("failed-to-unmarshal", err)
-			return errorResponse
-		}
-
-		response := auctiontypes.StartAuctionBid{
-			Rep: s.repGuid,
+			return errorResponseFor(err)
 		}
 
-		bid, err := s.rep.BidForStartAuction(inst)
+		response, err := s.rep.BidForStartAuction(inst)
 		if err != nil {
 			response.Error = err.Error()
-		} else {
-			response.Bid = bid
 		}
 
 		out, _ := json.Marshal(response)
 		return out
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.BidForStopAuction, func(payload []byte) []byte {
+	s.handle(subjects.BidForStopAuction, func(natsLog lager.Logger, payload []byte) []byte {
 		bidLog := natsLog.Session("bid-for-stop")
 
 		bidLog.Info("handling")
@@ -99,26 +107,19 @@ func (s *AuctionNATSServer) start(subjects nats.Subjects) {
 		err := json.Unmarshal(payload, &stopAuctionInfo)
 		if err != nil {
 			bidLog.Error("failed-to-unmarshal", err)
-			return errorResponse
+			return errorResponseFor(err)
 		}
 
-		response := auctiontypes.StopAuctionBid{
-			Rep: s.repGuid,
-		}
-
-		bid, instanceGuids, err := s.rep.BidForStopAuction(stopAuctionInfo)
+		response, err := s.rep.BidForStopAuction(stopAuctionInfo)
 		if err != nil {
 			response.Error = err.Error()
-		} else {
-			response.Bid = bid
-			response.InstanceGuids = instanceGuids
 		}
 
 		out, _ := json.Marshal(response)
 		return out
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.RebidThenTentativelyReserve, func(payload []byte) []byte {
+	s.handle(subjects.RebidThenTentativelyReserve, func(natsLog lager.Logger, payload []byte) []byte {
 		bidLog := natsLog.Session("re-bid-then-reserve")
 
 		bidLog.Info("handling")
@@ -128,25 +129,73 @@ func (s *AuctionNATSServer) start(subjects nats.Subjects) {
 		err := json.Unmarshal(payload, &inst)
 		if err != nil {
 			bidLog.Error("failed-to-unmarshal", err)
-			return errorResponse
+			return errorResponseFor(err)
 		}
 
-		response := auctiontypes.StartAuctionBid{
-			Rep: s.repGuid,
+		response, err := s.rep.RebidThenTentativelyReserve(inst)
+		if err != nil {
+			response.Error = err.Error()
 		}
 
-		bid, err := s.rep.RebidThenTentativelyReserve(inst)
+		out, _ := json.Marshal(response)
+		return out
+	})
+
+	s.handle(subjects.BidForBatchStartAuction, func(natsLog lager.Logger, payload []byte) []byte {
+		bidLog := natsLog.Session("bid-for-batch-start")
+
+		bidLog.Info("handling")
+
+		var insts []auctiontypes.StartAuctionInfo
+
+		err := json.Unmarshal(payload, &insts)
 		if err != nil {
-			response.Error = err.Error()
-		} else {
-			response.Bid = bid
+			bidLog.Error("failed-to-unmarshal", err)
+			return errorResponseFor(err)
+		}
+
+		response, err := s.rep.BidForBatchStartAuction(insts)
+		if err != nil {
+			response = auctiontypes.BatchStartAuctionBid{
+				Rep:   s.repGuid,
+				Error: err.Error(),
+			}
 		}
 
 		out, _ := json.Marshal(response)
 		return out
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.ReleaseReservation, func(payload []byte) []byte {
+	s.handle(subjects.TentativelyReserveBatch, func(natsLog lager.Logger, payload []byte) []byte {
+		reserveLog := natsLog.Session("reserve-batch")
+
+		reserveLog.Info("handling")
+
+		var insts []auctiontypes.StartAuctionInfo
+
+		err := json.Unmarshal(payload, &insts)
+		if err != nil {
+			reserveLog.Error("failed-to-unmarshal", err)
+			return errorResponseFor(err)
+		}
+
+		response := auctiontypes.StartAuctionReservations{}
+		for i, err := range s.rep.TentativelyReserveBatch(insts) {
+			reservation := auctiontypes.StartAuctionReservation{
+				Rep:          s.repGuid,
+				InstanceGuid: insts[i].InstanceGuid,
+			}
+			if err != nil {
+				reservation.Error = err.Error()
+			}
+			response = append(response, reservation)
+		}
+
+		out, _ := json.Marshal(response)
+		return out
+	})
+
+	s.handle(subjects.ReleaseReservation, func(natsLog lager.Logger, payload []byte) []byte {
 		releaseLog := natsLog.Session("release-reservation")
 
 		releaseLog.Info("handling")
@@ -156,15 +205,19 @@ func (s *AuctionNATSServer) start(subjects nats.Subjects) {
 		err := json.Unmarshal(payload, &inst)
 		if err != nil {
 			releaseLog.Error("failed-to-unmarshal", err)
-			return errorResponse
+			return errorResponseFor(err)
 		}
 
-		s.rep.ReleaseReservation(inst) //need to handle error
+		err = s.rep.ReleaseReservation(inst)
+		if err != nil {
+			releaseLog.Error("failed-to-release", err)
+			return errorResponseFor(err)
+		}
 
 		return successResponse
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.Run, func(payload []byte) []byte {
+	s.handle(subjects.Run, func(natsLog lager.Logger, payload []byte) []byte {
 		runLog := natsLog.Session("run")
 
 		runLog.Info("handling")
@@ -174,15 +227,19 @@ func (s *AuctionNATSServer) start(subjects nats.Subjects) {
 		err := json.Unmarshal(payload, &inst)
 		if err != nil {
 			runLog.Error("failed-to-unmarshal", err)
-			return errorResponse
+			return errorResponseFor(err)
 		}
 
-		s.rep.Run(inst) //need to handle error
+		err = s.rep.Run(inst)
+		if err != nil {
+			runLog.Error("failed-to-run", err)
+			return errorResponseFor(err)
+		}
 
 		return successResponse
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.Stop, func(payload []byte) []byte {
+	s.handle(subjects.Stop, func(natsLog lager.Logger, payload []byte) []byte {
 		stopLog := natsLog.Session("stop")
 
 		stopLog.Info("handling")
@@ -192,34 +249,70 @@ func (s *AuctionNATSServer) start(subjects nats.Subjects) {
 		err := json.Unmarshal(payload, &stopInstance)
 		if err != nil {
 			stopLog.Error("failed-to-unmarshal", err)
-			return errorResponse
+			return errorResponseFor(err)
+		}
+
+		err = s.rep.Stop(stopInstance)
+		if err != nil {
+			stopLog.Error("failed-to-stop", err)
+			return errorResponseFor(err)
+		}
+
+		return successResponse
+	})
+
+	s.handle(subjects.SetDraining, func(natsLog lager.Logger, payload []byte) []byte {
+		drainLog := natsLog.Session("set-draining")
+
+		var draining bool
+
+		err := json.Unmarshal(payload, &draining)
+		if err != nil {
+			drainLog.Error("failed-to-unmarshal", err)
+			return errorResponseFor(err)
 		}
 
-		s.rep.Stop(stopInstance) //need to handle error
+		drainLog.Info("handling", lager.Data{"draining": draining})
 
+		s.rep.SetDraining(draining)
 		return successResponse
 	})
 
 	//simulation only
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.Reset, func(payload []byte) []byte {
+	s.handle(subjects.Reset, func(natsLog lager.Logger, payload []byte) []byte {
 		s.rep.Reset()
 		return successResponse
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.SetSimulatedInstances, func(payload []byte) []byte {
+	s.handle(subjects.SetSimulatedInstances, func(natsLog lager.Logger, payload []byte) []byte {
 		var instances []auctiontypes.SimulatedInstance
 
 		err := json.Unmarshal(payload, &instances)
 		if err != nil {
-			return errorResponse
+			return errorResponseFor(err)
 		}
 
 		s.rep.SetSimulatedInstances(instances)
 		return successResponse
 	})
 
-	nats_muxer.HandleMuxedNATSRequest(s.client, subjects.SimulatedInstances, func(payload []byte) []byte {
+	s.handle(subjects.SetFailsToRun, func(natsLog lager.Logger, payload []byte) []byte {
+		var failsToRun bool
+
+		err := json.Unmarshal(payload, &failsToRun)
+		if err != nil {
+			return errorResponseFor(err)
+		}
+
+		err = s.rep.SetFailsToRun(failsToRun)
+		if err != nil {
+			return errorResponseFor(err)
+		}
+		return successResponse
+	})
+
+	s.handle(subjects.SimulatedInstances, func(natsLog lager.Logger, payload []byte) []byte {
 		jinstances, _ := json.Marshal(s.rep.SimulatedInstances())
 		return jinstances
 	})
@@ -230,3 +323,25 @@ func (s *AuctionNATSServer) stop(subjects nats.Subjects) {
 		s.client.UnsubscribeAll(topic)
 	}
 }
+
+// handle logs every request under the trace ID it was sent with, times it, and counts the ones answered with an error
+func (s *AuctionNATSServer) handle(subject string, handler func(natsLog lager.Logger, payload []byte) []byte) {
+	request := strings.TrimPrefix(subject, s.repGuid+".")
+
+	nats_muxer.HandleTracedMuxedNATSRequest(s.client, subject, func(traceID string, payload []byte) []byte {
+		natsLog := s.logger.Session("nats-handler")
+		if traceID != "" {
+			natsLog = s.logger.Session("nats-handler", lager.Data{
+				"trace-id": traceID,
+			})
+		}
+
+		t := time.Now()
+		response := handler(natsLog, payload)
+		requestDuration.Observe(time.Since(t).Seconds(), request)
+		if bytes.HasPrefix(response, errorResponse) {
+			requestFailures.Inc(request)
+		}
+		return response
+	})
+}
@@ -0,0 +1,8 @@
+package auction_nats_server
+
+import "github.com/cloudfoundry-incubator/auction/metrics"
+
+var (
+	requestDuration = metrics.Default.Histogram("auction_rep_request_duration_seconds", "Time the rep spent handling requests.", []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}, "request")
+	requestFailures = metrics.Default.Counter("auction_rep_request_errors_total", "Requests the rep answered with an error.", "request")
+)
@@ -24,6 +24,7 @@ type NATSMuxerClient struct {
 
 type message struct {
 	CorrelationID int64
+	TraceID       string `json:",omitempty"`
 	Payload       []byte
 }
 
@@ -56,6 +57,11 @@ func (c *NATSMuxerClient) Shutdown() error {
 }
 
 func (c *NATSMuxerClient) Request(subject string, payload []byte, timeout time.Duration) ([]byte, error) {
+	return c.RequestWithTraceID(subject, "", payload, timeout)
+}
+
+// the trace ID is handed to the handler alongside the payload, see HandleTracedMuxedNATSRequest
+func (c *NATSMuxerClient) RequestWithTraceID(subject string, traceID string, payload []byte, timeout time.Duration) ([]byte, error) {
 	response := make(chan []byte, 0)
 	correlationID := atomic.AddInt64(&c.correlationID, 1)
 
@@ -71,6 +77,7 @@ func (c *NATSMuxerClient) Request(subject string, payload []byte, timeout time.D
 
 	msg := message{
 		CorrelationID: correlationID,
+		TraceID:       traceID,
 		Payload:       payload,
 	}
 
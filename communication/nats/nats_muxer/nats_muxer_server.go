@@ -8,7 +8,16 @@ import (
 
 type MuxedHandler func([]byte) []byte
 
+// TracedMuxedHandler is also given the trace ID the request was sent with ("" if none)
+type TracedMuxedHandler func(traceID string, payload []byte) []byte
+
 func HandleMuxedNATSRequest(client yagnats.NATSClient, subject string, callback MuxedHandler) (int64, error) {
+	return HandleTracedMuxedNATSRequest(client, subject, func(traceID string, payload []byte) []byte {
+		return callback(payload)
+	})
+}
+
+func HandleTracedMuxedNATSRequest(client yagnats.NATSClient, subject string, callback TracedMuxedHandler) (int64, error) {
 	return client.Subscribe(subject, func(msg *yagnats.Message) {
 		request := message{}
 		err := json.Unmarshal(msg.Payload, &request)
@@ -16,10 +25,11 @@ func HandleMuxedNATSRequest(client yagnats.NATSClient, subject string, callback
 			return
 		}
 
-		payload := callback(request.Payload)
+		payload := callback(request.TraceID, request.Payload)
 
 		response := message{
 			CorrelationID: request.CorrelationID,
+			TraceID:       request.TraceID,
 			Payload:       payload,
 		}
 
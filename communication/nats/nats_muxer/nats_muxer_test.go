@@ -12,7 +12,7 @@ import (
 )
 
 var _ = Describe("Nats Muxer", func() {
-	var subscriptionID1, subscriptionID2 int64
+	var subscriptionID1, subscriptionID2, subscriptionID3 int64
 	var client *NATSMuxerClient
 
 	BeforeEach(func() {
@@ -35,6 +35,11 @@ var _ = Describe("Nats Muxer", func() {
 		})
 
 		Ω(err).ShouldNot(HaveOccurred())
+
+		subscriptionID3, err = HandleTracedMuxedNATSRequest(natsClient, "trace", func(traceID string, payload []byte) []byte {
+			return []byte(traceID)
+		})
+		Ω(err).ShouldNot(HaveOccurred())
 	})
 
 	AfterEach(func() {
@@ -44,6 +49,9 @@ var _ = Describe("Nats Muxer", func() {
 		err = natsClient.Unsubscribe(subscriptionID2)
 		Ω(err).ShouldNot(HaveOccurred())
 
+		err = natsClient.Unsubscribe(subscriptionID3)
+		Ω(err).ShouldNot(HaveOccurred())
+
 		err = client.Shutdown()
 		Ω(err).ShouldNot(HaveOccurred())
 	})
@@ -81,6 +89,16 @@ var _ = Describe("Nats Muxer", func() {
 		wg.Wait()
 	})
 
+	It("should hand the trace ID to the handler", func() {
+		response, err := client.RequestWithTraceID("trace", "some-trace-id", []byte("foo"), time.Second)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(response)).Should(Equal("some-trace-id"))
+
+		response, err = client.Request("trace", []byte("foo"), time.Second)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(response).Should(BeEmpty())
+	})
+
 	It("should be able to timeout", func() {
 		response, err := client.Request("foo", []byte("foo"), time.Second)
 		Ω(err).Should(MatchError(TimeoutError))
@@ -4,29 +4,39 @@ import "reflect"
 
 type Subjects struct {
 	TotalResources              string
+	Zone                        string
 	Reset                       string
 	SimulatedInstances          string
 	SetSimulatedInstances       string
+	SetFailsToRun               string
 	BidForStartAuction          string
 	BidForStopAuction           string
 	RebidThenTentativelyReserve string
+	BidForBatchStartAuction     string
+	TentativelyReserveBatch     string
 	ReleaseReservation          string
 	Run                         string
 	Stop                        string
+	SetDraining                 string
 }
 
 func NewSubjects(repGuid string) Subjects {
 	return Subjects{
 		TotalResources:              repGuid + ".total-resources",
+		Zone:                        repGuid + ".zone",
 		Reset:                       repGuid + ".reset",
 		SimulatedInstances:          repGuid + ".simulated-instances",
 		SetSimulatedInstances:       repGuid + ".set-simulated-instances",
+		SetFailsToRun:               repGuid + ".set-fails-to-run",
 		BidForStartAuction:          repGuid + ".bid-for-start-auction",
 		BidForStopAuction:           repGuid + ".bid-for-stop-auction",
 		RebidThenTentativelyReserve: repGuid + ".rebid-then-tentatively-reserve",
+		BidForBatchStartAuction:     repGuid + ".bid-for-batch-start-auction",
+		TentativelyReserveBatch:     repGuid + ".tentatively-reserve-batch",
 		ReleaseReservation:          repGuid + ".release-reservation",
 		Run:                         repGuid + ".run",
 		Stop:                        repGuid + ".stop",
+		SetDraining:                 repGuid + ".set-draining",
 	}
 }
 
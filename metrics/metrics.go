@@ -0,0 +1,220 @@
+// Package metrics keeps counters, gauges and histograms and renders them in the Prometheus text format.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default is the registry the auction packages record into and the nodes serve on /metrics
+var Default = NewRegistry()
+
+type Registry struct {
+	lock     *sync.Mutex
+	families []*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		lock: &sync.Mutex{},
+	}
+}
+
+type Counter struct{ *family }
+type Gauge struct{ *family }
+type Histogram struct{ *family }
+
+// a family is every series of one metric, one series per combination of label values
+type family struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	buckets    []float64
+
+	lock   *sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+func (r *Registry) Counter(name string, help string, labelNames ...string) Counter {
+	return Counter{r.register(name, help, "counter", labelNames, nil)}
+}
+
+func (r *Registry) Gauge(name string, help string, labelNames ...string) Gauge {
+	return Gauge{r.register(name, help, "gauge", labelNames, nil)}
+}
+
+// buckets are the upper bounds of the histogram's buckets, in increasing order
+func (r *Registry) Histogram(name string, help string, buckets []float64, labelNames ...string) Histogram {
+	return Histogram{r.register(name, help, "histogram", labelNames, buckets)}
+}
+
+func (r *Registry) register(name string, help string, kind string, labelNames []string, buckets []float64) *family {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, f := range r.families {
+		if f.name == name {
+			panic("metric registered twice: " + name)
+		}
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		buckets:    buckets,
+		lock:       &sync.Mutex{},
+		series:     map[string]*series{},
+	}
+	r.families = append(r.families, f)
+
+	return f
+}
+
+func (c Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c Counter) Add(delta float64, labelValues ...string) {
+	c.update(labelValues, func(s *series) {
+		s.value += delta
+	})
+}
+
+func (g Gauge) Set(value float64, labelValues ...string) {
+	g.update(labelValues, func(s *series) {
+		s.value = value
+	})
+}
+
+func (g Gauge) Add(delta float64, labelValues ...string) {
+	g.update(labelValues, func(s *series) {
+		s.value += delta
+	})
+}
+
+func (h Histogram) Observe(value float64, labelValues ...string) {
+	h.update(labelValues, func(s *series) {
+		for i, bucket := range h.buckets {
+			if value <= bucket {
+				s.counts[i]++
+			}
+		}
+		s.value += value
+		s.count++
+	})
+}
+
+func (f *family) update(labelValues []string, update func(s *series)) {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s takes %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{
+			labelValues: append([]string{}, labelValues...),
+			counts:      make([]uint64, len(f.buckets)),
+		}
+		f.series[key] = s
+	}
+
+	update(s)
+}
+
+// WriteTo renders every metric in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.lock.Lock()
+	families := append([]*family{}, r.families...)
+	r.lock.Unlock()
+
+	out := &bytes.Buffer{}
+	for _, f := range families {
+		f.writeTo(out)
+	}
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WriteTo(w)
+}
+
+func (f *family) writeTo(out *bytes.Buffer) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	fmt.Fprintf(out, "# HELP %s %s\n", f.name, helpEscaper.Replace(f.help))
+	fmt.Fprintf(out, "# TYPE %s %s\n", f.name, f.kind)
+
+	keys := []string{}
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != "histogram" {
+			fmt.Fprintf(out, "%s%s %s\n", f.name, f.labels(s.labelValues, ""), formatValue(s.value))
+			continue
+		}
+
+		for i, bucket := range f.buckets {
+			fmt.Fprintf(out, "%s_bucket%s %d\n", f.name, f.labels(s.labelValues, formatValue(bucket)), s.counts[i])
+		}
+		fmt.Fprintf(out, "%s_bucket%s %d\n", f.name, f.labels(s.labelValues, "+Inf"), s.count)
+		fmt.Fprintf(out, "%s_sum%s %s\n", f.name, f.labels(s.labelValues, ""), formatValue(s.value))
+		fmt.Fprintf(out, "%s_count%s %d\n", f.name, f.labels(s.labelValues, ""), s.count)
+	}
+}
+
+func (f *family) labels(labelValues []string, le string) string {
+	pairs := []string{}
+	for i, name := range f.labelNames {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, labelValueEscaper.Replace(labelValues[i])))
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf("le=%q", le))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// the text format only knows these escapes: anything else, tabs and unicode included, is written as is
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", value)
+}
@@ -0,0 +1,225 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/auction/metrics"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	var registry *metrics.Registry
+
+	//render returns the lines of the registry's exposition
+	render := func() []string {
+		out := &bytes.Buffer{}
+		_, err := registry.WriteTo(out)
+		Ω(err).ShouldNot(HaveOccurred())
+		return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	}
+
+	BeforeEach(func() {
+		registry = metrics.NewRegistry()
+	})
+
+	Describe("counters", func() {
+		It("should count every series of label values apart, in order", func() {
+			counter := registry.Counter("auctions_total", "Auctions held.", "algorithm", "outcome")
+			counter.Inc("pick_best", "won")
+			counter.Inc("pick_best", "won")
+			counter.Add(2.5, "pick_best", "lost")
+			counter.Inc("all_rebid", "won")
+
+			Ω(render()).Should(Equal([]string{
+				"# HELP auctions_total Auctions held.",
+				"# TYPE auctions_total counter",
+				`auctions_total{algorithm="all_rebid",outcome="won"} 1`,
+				`auctions_total{algorithm="pick_best",outcome="lost"} 2.5`,
+				`auctions_total{algorithm="pick_best",outcome="won"} 2`,
+			}))
+		})
+
+		It("should render a counter without labels bare", func() {
+			registry.Counter("requests_total", "Requests handled.").Inc()
+
+			Ω(render()).Should(ContainElement("requests_total 1"))
+		})
+
+		It("should render a metric nothing was recorded into with its help and type only", func() {
+			registry.Counter("requests_total", "Requests handled.", "rep")
+
+			Ω(render()).Should(Equal([]string{
+				"# HELP requests_total Requests handled.",
+				"# TYPE requests_total counter",
+			}))
+		})
+
+		It("should render metrics in the order they were registered", func() {
+			registry.Counter("b_total", "B.").Inc()
+			registry.Gauge("a", "A.").Set(1)
+
+			Ω(render()).Should(Equal([]string{
+				"# HELP b_total B.",
+				"# TYPE b_total counter",
+				"b_total 1",
+				"# HELP a A.",
+				"# TYPE a gauge",
+				"a 1",
+			}))
+		})
+
+		It("should not lose increments made concurrently", func() {
+			counter := registry.Counter("requests_total", "Requests handled.", "rep")
+
+			wg := &sync.WaitGroup{}
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					rep := []string{"rep-a", "rep-b"}[i%2]
+					for j := 0; j < 200; j++ {
+						counter.Inc(rep)
+					}
+				}(i)
+			}
+
+			//render while the increments are happening too
+			for i := 0; i < 10; i++ {
+				render()
+			}
+			wg.Wait()
+
+			Ω(render()).Should(ContainElement(`requests_total{rep="rep-a"} 5000`))
+			Ω(render()).Should(ContainElement(`requests_total{rep="rep-b"} 5000`))
+		})
+	})
+
+	Describe("gauges", func() {
+		It("should hold the last value set, moved by what is added", func() {
+			gauge := registry.Gauge("reservations_held", "Reservations held.", "rep")
+			gauge.Set(3, "rep-a")
+			gauge.Set(7, "rep-a")
+			gauge.Add(-2, "rep-a")
+			gauge.Add(1, "rep-b")
+
+			Ω(render()).Should(Equal([]string{
+				"# HELP reservations_held Reservations held.",
+				"# TYPE reservations_held gauge",
+				`reservations_held{rep="rep-a"} 5`,
+				`reservations_held{rep="rep-b"} 1`,
+			}))
+		})
+
+		It("should render values below zero and fractions", func() {
+			gauge := registry.Gauge("drift", "Drift.")
+			gauge.Set(-0.25)
+
+			Ω(render()).Should(ContainElement("drift -0.25"))
+		})
+	})
+
+	Describe("histograms", func() {
+		var histogram metrics.Histogram
+
+		BeforeEach(func() {
+			histogram = registry.Histogram("rounds", "Rounds held.", []float64{1, 5, 10}, "algorithm")
+		})
+
+		It("should count a value on a bucket's upper bound into that bucket, and every bucket above it", func() {
+			histogram.Observe(1, "pick_best")
+			histogram.Observe(5, "pick_best")
+			histogram.Observe(10, "pick_best")
+
+			Ω(render()).Should(Equal([]string{
+				"# HELP rounds Rounds held.",
+				"# TYPE rounds histogram",
+				`rounds_bucket{algorithm="pick_best",le="1"} 1`,
+				`rounds_bucket{algorithm="pick_best",le="5"} 2`,
+				`rounds_bucket{algorithm="pick_best",le="10"} 3`,
+				`rounds_bucket{algorithm="pick_best",le="+Inf"} 3`,
+				`rounds_sum{algorithm="pick_best"} 16`,
+				`rounds_count{algorithm="pick_best"} 3`,
+			}))
+		})
+
+		It("should count a value just above a bound into the next bucket, and one above every bound into +Inf only", func() {
+			histogram.Observe(0, "pick_best")
+			histogram.Observe(1.5, "pick_best")
+			histogram.Observe(10.5, "pick_best")
+
+			Ω(render()).Should(Equal([]string{
+				"# HELP rounds Rounds held.",
+				"# TYPE rounds histogram",
+				`rounds_bucket{algorithm="pick_best",le="1"} 1`,
+				`rounds_bucket{algorithm="pick_best",le="5"} 2`,
+				`rounds_bucket{algorithm="pick_best",le="10"} 2`,
+				`rounds_bucket{algorithm="pick_best",le="+Inf"} 3`,
+				`rounds_sum{algorithm="pick_best"} 12`,
+				`rounds_count{algorithm="pick_best"} 3`,
+			}))
+		})
+	})
+
+	Describe("labels", func() {
+		It("should escape backslashes, double quotes and newlines in label values", func() {
+			counter := registry.Counter("requests_total", "Requests handled.", "reason")
+			counter.Inc(`C:\path`)
+			counter.Inc(`say "hi"`)
+			counter.Inc("two\nlines")
+
+			Ω(render()).Should(ContainElement(`requests_total{reason="C:\\path"} 1`))
+			Ω(render()).Should(ContainElement(`requests_total{reason="say \"hi\""} 1`))
+			Ω(render()).Should(ContainElement(`requests_total{reason="two\nlines"} 1`))
+		})
+
+		It("should write any other character as is", func() {
+			counter := registry.Counter("requests_total", "Requests handled.", "reason")
+			counter.Inc("tab\there, ünïcödé")
+
+			Ω(render()).Should(ContainElement("requests_total{reason=\"tab\there, ünïcödé\"} 1"))
+		})
+
+		It("should escape backslashes and newlines in help", func() {
+			registry.Counter("requests_total", "Requests\nhandled by C:\\rep.")
+
+			Ω(render()).Should(ContainElement(`# HELP requests_total Requests\nhandled by C:\\rep.`))
+		})
+
+		It("should panic when given the wrong number of label values", func() {
+			counter := registry.Counter("requests_total", "Requests handled.", "rep")
+
+			Ω(func() { counter.Inc() }).Should(Panic())
+			Ω(func() { counter.Inc("rep-a", "extra") }).Should(Panic())
+		})
+	})
+
+	Describe("registering", func() {
+		It("should panic when a name is registered twice", func() {
+			registry.Counter("requests_total", "Requests handled.")
+
+			Ω(func() { registry.Gauge("requests_total", "Requests handled.") }).Should(Panic())
+		})
+
+		It("should keep registries apart", func() {
+			registry.Counter("requests_total", "Requests handled.").Inc()
+
+			Ω(func() { metrics.NewRegistry().Counter("requests_total", "Requests handled.") }).ShouldNot(Panic())
+		})
+	})
+
+	Describe("serving", func() {
+		It("should serve the text format", func() {
+			registry.Counter("requests_total", "Requests handled.").Inc()
+
+			recorder := httptest.NewRecorder()
+			registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+			Ω(recorder.Header().Get("Content-Type")).Should(Equal("text/plain; version=0.0.4"))
+			Ω(recorder.Body.String()).Should(ContainSubstring("requests_total 1\n"))
+		})
+	})
+})
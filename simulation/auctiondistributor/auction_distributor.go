@@ -13,11 +13,13 @@ import (
 
 type StartAuctionCommunicator func(auctiontypes.StartAuctionRequest) (auctiontypes.StartAuctionResult, error)
 type StopAuctionCommunicator func(auctiontypes.StopAuctionRequest) (auctiontypes.StopAuctionResult, error)
+type StartAuctionBatchCommunicator func(auctiontypes.StartAuctionBatchRequest) ([]auctiontypes.StartAuctionResult, error)
 
 type AuctionDistributor struct {
 	client            auctiontypes.SimulationRepPoolClient
 	startCommunicator StartAuctionCommunicator
 	stopCommunicator  StopAuctionCommunicator
+	batchCommunicator StartAuctionBatchCommunicator
 	maxConcurrent     int
 }
 
@@ -32,6 +34,9 @@ func NewInProcessAuctionDistributor(client auctiontypes.SimulationRepPoolClient,
 		stopCommunicator: func(auctionRequest auctiontypes.StopAuctionRequest) (auctiontypes.StopAuctionResult, error) {
 			return auctionRunner.RunLRPStopAuction(auctionRequest)
 		},
+		batchCommunicator: func(auctionRequest auctiontypes.StartAuctionBatchRequest) ([]auctiontypes.StartAuctionResult, error) {
+			return auctionRunner.RunLRPStartAuctionBatch(auctionRequest)
+		},
 	}
 }
 
@@ -41,6 +46,7 @@ func NewRemoteAuctionDistributor(hosts []string, client auctiontypes.SimulationR
 		maxConcurrent:     maxConcurrent,
 		startCommunicator: newHttpRemoteAuctions(hosts).RemoteStartAuction,
 		stopCommunicator:  newHttpRemoteAuctions(hosts).RemoteStopAuction,
+		batchCommunicator: newHttpRemoteAuctions(hosts).RemoteStartAuctionBatch,
 	}
 }
 
@@ -50,29 +56,43 @@ func (ad *AuctionDistributor) HoldAuctionsFor(
 	instances []models.LRPStartAuction,
 	representatives []string,
 	rules auctiontypes.StartAuctionRules,
+) *visualization.Report {
+	auctionRequests := []auctiontypes.StartAuctionRequest{}
+	for _, inst := range instances {
+		auctionRequests = append(auctionRequests, auctiontypes.StartAuctionRequest{
+			LRPStartAuction: inst,
+			RepGuids:        representatives,
+			Rules:           rules,
+		})
+	}
+
+	return ad.HoldAuctionRequestsFor(scenarioDescription, numRepresentatives, auctionRequests, representatives)
+}
+
+func (ad *AuctionDistributor) HoldAuctionRequestsFor(
+	scenarioDescription string,
+	numRepresentatives int,
+	auctionRequests []auctiontypes.StartAuctionRequest,
+	representatives []string,
 ) *visualization.Report {
 	fmt.Printf("\nStarting Auctions: '%s' on %d Executors\n\n", scenarioDescription, numRepresentatives)
-	bar := pb.StartNew(len(instances))
+	bar := pb.StartNew(len(auctionRequests))
 
 	t := time.Now()
 	semaphore := make(chan bool, ad.maxConcurrent)
 	c := make(chan auctiontypes.StartAuctionResult)
-	for _, inst := range instances {
-		go func(inst models.LRPStartAuction) {
+	for _, auctionRequest := range auctionRequests {
+		go func(auctionRequest auctiontypes.StartAuctionRequest) {
 			semaphore <- true
-			result, _ := ad.startCommunicator(auctiontypes.StartAuctionRequest{
-				LRPStartAuction: inst,
-				RepGuids:        representatives,
-				Rules:           rules,
-			})
+			result, _ := ad.startCommunicator(auctionRequest)
 			result.Duration = time.Since(t)
 			c <- result
 			<-semaphore
-		}(inst)
+		}(auctionRequest)
 	}
 
 	results := []auctiontypes.StartAuctionResult{}
-	for _ = range instances {
+	for _ = range auctionRequests {
 		results = append(results, <-c)
 		bar.Increment()
 	}
@@ -84,6 +104,65 @@ func (ad *AuctionDistributor) HoldAuctionsFor(
 		RepGuids:        representatives,
 		AuctionResults:  results,
 		InstancesByRep:  visualization.FetchAndSortInstances(ad.client, representatives),
+		ZonesByRep:      visualization.FetchZones(ad.client, representatives),
+		AuctionDuration: duration,
+	}
+
+	return report
+}
+
+func (ad *AuctionDistributor) HoldBatchAuctionsFor(
+	scenarioDescription string,
+	numRepresentatives int,
+	instances []models.LRPStartAuction,
+	representatives []string,
+	rules auctiontypes.StartAuctionRules,
+	batchSize int,
+) *visualization.Report {
+	fmt.Printf("\nStarting Batch Auctions: '%s' on %d Executors in batches of %d\n\n", scenarioDescription, numRepresentatives, batchSize)
+	bar := pb.StartNew(len(instances))
+
+	t := time.Now()
+	semaphore := make(chan bool, ad.maxConcurrent)
+	c := make(chan []auctiontypes.StartAuctionResult)
+	numBatches := 0
+	for start := 0; start < len(instances); start += batchSize {
+		end := start + batchSize
+		if end > len(instances) {
+			end = len(instances)
+		}
+
+		numBatches++
+		go func(batch []models.LRPStartAuction) {
+			semaphore <- true
+			results, _ := ad.batchCommunicator(auctiontypes.StartAuctionBatchRequest{
+				LRPStartAuctions: batch,
+				RepGuids:         representatives,
+				Rules:            rules,
+			})
+			for i := range results {
+				results[i].Duration = time.Since(t)
+			}
+			c <- results
+			<-semaphore
+		}(instances[start:end])
+	}
+
+	results := []auctiontypes.StartAuctionResult{}
+	for i := 0; i < numBatches; i++ {
+		batchResults := <-c
+		results = append(results, batchResults...)
+		bar.Add(len(batchResults))
+	}
+
+	bar.Finish()
+
+	duration := time.Since(t)
+	report := &visualization.Report{
+		RepGuids:        representatives,
+		AuctionResults:  results,
+		InstancesByRep:  visualization.FetchAndSortInstances(ad.client, representatives),
+		ZonesByRep:      visualization.FetchZones(ad.client, representatives),
 		AuctionDuration: duration,
 	}
 
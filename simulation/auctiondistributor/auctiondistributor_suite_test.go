@@ -0,0 +1,13 @@
+package auctiondistributor
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAuctiondistributor(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auctiondistributor Suite")
+}
@@ -3,6 +3,8 @@ package auctiondistributor
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 
@@ -10,6 +12,9 @@ import (
 	"github.com/cloudfoundry-incubator/auction/util"
 )
 
+// the header the simulation auctioneer puts a failed auction's error in
+const auctionErrorHeader = "X-Auction-Error"
+
 type httpRemoteAuctions struct {
 	hosts []string
 }
@@ -19,43 +24,73 @@ func newHttpRemoteAuctions(hosts []string) *httpRemoteAuctions {
 }
 
 func (h *httpRemoteAuctions) RemoteStartAuction(auctionRequest auctiontypes.StartAuctionRequest) (auctiontypes.StartAuctionResult, error) {
+	var result auctiontypes.StartAuctionResult
+	err := h.post("/start-auction", auctionRequest, &result)
+	return result, err
+}
+
+func (h *httpRemoteAuctions) RemoteStopAuction(auctionRequest auctiontypes.StopAuctionRequest) (auctiontypes.StopAuctionResult, error) {
+	var result auctiontypes.StopAuctionResult
+	err := h.post("/stop-auction", auctionRequest, &result)
+	return result, err
+}
+
+func (h *httpRemoteAuctions) RemoteStartAuctionBatch(auctionRequest auctiontypes.StartAuctionBatchRequest) ([]auctiontypes.StartAuctionResult, error) {
+	var results []auctiontypes.StartAuctionResult
+	err := h.post("/start-auction-batch", auctionRequest, &results)
+	return results, err
+}
+
+// post holds an auction on one of the auctioneers and decodes its result, which a failed auction answers with too
+func (h *httpRemoteAuctions) post(path string, auctionRequest interface{}, result interface{}) error {
 	host := h.hosts[util.R.Intn(len(h.hosts))]
 
 	payload, _ := json.Marshal(auctionRequest)
-	res, err := http.Post("http://"+host+"/start-auction", "application/json", bytes.NewReader(payload))
+	res, err := http.Post("http://"+host+path, "application/json", bytes.NewReader(payload))
 	if err != nil {
-		return auctiontypes.StartAuctionResult{}, err
+		return err
 	}
 
 	defer res.Body.Close()
 	data, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return auctiontypes.StartAuctionResult{}, err
+		return err
 	}
 
-	var result auctiontypes.StartAuctionResult
-	json.Unmarshal(data, &result)
-
-	return result, nil
-}
-
-func (h *httpRemoteAuctions) RemoteStopAuction(auctionRequest auctiontypes.StopAuctionRequest) (auctiontypes.StopAuctionResult, error) {
-	host := h.hosts[util.R.Intn(len(h.hosts))]
+	//not an auction's answer (e.g. a bad request): there is no result to decode
+	auctionError := res.Header.Get(auctionErrorHeader)
+	if res.StatusCode != http.StatusOK && auctionError == "" {
+		return fmt.Errorf("auctioneer %s answered %s: %s", host, res.Status, bytes.TrimSpace(data))
+	}
 
-	payload, _ := json.Marshal(auctionRequest)
-	res, err := http.Post("http://"+host+"/stop-auction", "application/json", bytes.NewReader(payload))
+	err = json.Unmarshal(data, result)
 	if err != nil {
-		return auctiontypes.StopAuctionResult{}, err
+		return err
 	}
 
-	defer res.Body.Close()
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return auctiontypes.StopAuctionResult{}, err
+	if res.StatusCode != http.StatusOK {
+		return remoteError(auctionError)
 	}
 
-	var result auctiontypes.StopAuctionResult
-	json.Unmarshal(data, &result)
+	return nil
+}
+
+// the auction errors callers compare against come back as themselves, any other as its text
+func remoteError(text string) error {
+	for _, err := range []error{
+		auctiontypes.InsufficientResources,
+		auctiontypes.ConstraintNotSatisfied,
+		auctiontypes.AuctionInProgress,
+		auctiontypes.NothingToStop,
+		auctiontypes.AuctionDeadlineExceeded,
+		auctiontypes.AuctionCancelled,
+		auctiontypes.RepDraining,
+		auctiontypes.RepBusy,
+	} {
+		if err.Error() == text {
+			return err
+		}
+	}
 
-	return result, nil
+	return errors.New(text)
 }
@@ -0,0 +1,92 @@
+package auctiondistributor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Remote auctions over HTTP", func() {
+	var server *httptest.Server
+	var remote *httpRemoteAuctions
+
+	//answer starts an auctioneer that answers every request with the given status, error header and body
+	answer := func(status int, auctionError string, body string) {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if auctionError != "" {
+				w.Header().Set(auctionErrorHeader, auctionError)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+		remote = newHttpRemoteAuctions([]string{strings.TrimPrefix(server.URL, "http://")})
+	}
+
+	encode := func(v interface{}) string {
+		out, err := json.Marshal(v)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(out)
+	}
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should return the result of a placed auction", func() {
+		answer(http.StatusOK, "", encode(auctiontypes.StartAuctionResult{Winner: "rep-1"}))
+
+		result, err := remote.RemoteStartAuction(auctiontypes.StartAuctionRequest{})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(result.Winner).Should(Equal("rep-1"))
+	})
+
+	It("should return a failed auction's result with its error", func() {
+		answer(http.StatusGatewayTimeout, auctiontypes.AuctionDeadlineExceeded.Error(), encode(auctiontypes.StartAuctionResult{NumRounds: 2}))
+
+		result, err := remote.RemoteStartAuction(auctiontypes.StartAuctionRequest{})
+		Ω(err).Should(Equal(auctiontypes.AuctionDeadlineExceeded))
+		Ω(result.NumRounds).Should(Equal(2))
+	})
+
+	It("should return a failed batch's results with its error", func() {
+		answer(http.StatusUnprocessableEntity, auctiontypes.ConstraintNotSatisfied.Error(), encode([]auctiontypes.StartAuctionResult{{Winner: "rep-1"}, {}}))
+
+		results, err := remote.RemoteStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{})
+		Ω(err).Should(Equal(auctiontypes.ConstraintNotSatisfied))
+		Ω(results).Should(HaveLen(2))
+		Ω(results[0].Winner).Should(Equal("rep-1"))
+	})
+
+	It("should return a stop auction's error", func() {
+		answer(http.StatusNotFound, auctiontypes.NothingToStop.Error(), encode(auctiontypes.StopAuctionResult{}))
+
+		_, err := remote.RemoteStopAuction(auctiontypes.StopAuctionRequest{})
+		Ω(err).Should(Equal(auctiontypes.NothingToStop))
+	})
+
+	It("should return errors it does not know by their text", func() {
+		answer(http.StatusInternalServerError, "boom", encode(auctiontypes.StartAuctionResult{}))
+
+		_, err := remote.RemoteStartAuction(auctiontypes.StartAuctionRequest{})
+		Ω(err).Should(MatchError("boom"))
+	})
+
+	It("should fail when the auctioneer does not answer with an auction's result", func() {
+		answer(http.StatusBadRequest, "", "")
+
+		_, err := remote.RemoteStartAuction(auctiontypes.StartAuctionRequest{})
+		Ω(err).Should(MatchError(ContainSubstring("400")))
+	})
+
+	It("should fail when the result does not decode", func() {
+		answer(http.StatusOK, "", "not json")
+
+		_, err := remote.RemoteStartAuction(auctiontypes.StartAuctionRequest{})
+		Ω(err).Should(HaveOccurred())
+	})
+})
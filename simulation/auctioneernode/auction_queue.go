@@ -0,0 +1,180 @@
+package main
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/auction/util"
+)
+
+var QueueFull = errors.New("auction queue is full")
+
+const (
+	auctionPending  = "pending"
+	auctionInFlight = "in-flight"
+	auctionDone     = "done"
+)
+
+// AuctionStatus is what the queue endpoints report about a submitted auction
+type AuctionStatus struct {
+	ID          string
+	Priority    int
+	State       string
+	SubmittedAt time.Time
+	Result      *auctiontypes.StartAuctionResult `json:",omitempty"`
+	Error       string                           `json:",omitempty"`
+}
+
+type queuedAuction struct {
+	status  AuctionStatus
+	request auctiontypes.StartAuctionRequest
+	seq     int
+}
+
+// auctionQueue hands submitted auctions to workers highest priority first, first come first served within a priority.
+// Finished auctions are remembered for the retention period so their submitters can poll for the result.
+type auctionQueue struct {
+	lock      *sync.Mutex
+	available *sync.Cond
+	maxDepth  int
+	retention time.Duration
+
+	pending  pendingAuctions
+	auctions map[string]*queuedAuction
+	seq      int
+}
+
+func newAuctionQueue(maxDepth int, retention time.Duration) *auctionQueue {
+	lock := &sync.Mutex{}
+	return &auctionQueue{
+		lock:      lock,
+		available: sync.NewCond(lock),
+		maxDepth:  maxDepth,
+		retention: retention,
+		auctions:  map[string]*queuedAuction{},
+	}
+}
+
+// Submit queues an auction behind the pending ones of its request's Priority or higher
+func (q *auctionQueue) Submit(auctionRequest auctiontypes.StartAuctionRequest) (string, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.pending) >= q.maxDepth {
+		return "", QueueFull
+	}
+
+	q.seq++
+	auction := &queuedAuction{
+		status: AuctionStatus{
+			ID:          util.RandomGuid(),
+			Priority:    auctionRequest.Priority,
+			State:       auctionPending,
+			SubmittedAt: time.Now(),
+		},
+		request: auctionRequest,
+		seq:     q.seq,
+	}
+
+	q.auctions[auction.status.ID] = auction
+	heap.Push(&q.pending, auction)
+	q.available.Signal()
+
+	return auction.status.ID, nil
+}
+
+// Next blocks until there is an auction to hold and marks it in flight
+func (q *auctionQueue) Next() (string, auctiontypes.StartAuctionRequest) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for len(q.pending) == 0 {
+		q.available.Wait()
+	}
+
+	auction := heap.Pop(&q.pending).(*queuedAuction)
+	auction.status.State = auctionInFlight
+
+	return auction.status.ID, auction.request
+}
+
+func (q *auctionQueue) Finish(id string, result auctiontypes.StartAuctionResult, err error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	auction, ok := q.auctions[id]
+	if !ok {
+		return
+	}
+
+	auction.status.State = auctionDone
+	auction.status.Result = &result
+	if err != nil {
+		auction.status.Error = err.Error()
+	}
+
+	time.AfterFunc(q.retention, func() {
+		q.lock.Lock()
+		delete(q.auctions, id)
+		q.lock.Unlock()
+	})
+}
+
+func (q *auctionQueue) Status(id string) (AuctionStatus, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	auction, ok := q.auctions[id]
+	if !ok {
+		return AuctionStatus{}, false
+	}
+
+	return auction.status, true
+}
+
+// Unfinished lists the in-flight auctions, then the pending ones in the order they will be held
+func (q *auctionQueue) Unfinished() []AuctionStatus {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	statuses := []AuctionStatus{}
+	for _, auction := range q.auctions {
+		if auction.status.State == auctionInFlight {
+			statuses = append(statuses, auction.status)
+		}
+	}
+
+	pending := make(pendingAuctions, len(q.pending))
+	copy(pending, q.pending)
+	for len(pending) > 0 {
+		statuses = append(statuses, heap.Pop(&pending).(*queuedAuction).status)
+	}
+
+	return statuses
+}
+
+type pendingAuctions []*queuedAuction
+
+func (p pendingAuctions) Len() int      { return len(p) }
+func (p pendingAuctions) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p pendingAuctions) Less(i, j int) bool {
+	if p[i].status.Priority != p[j].status.Priority {
+		return p[i].status.Priority > p[j].status.Priority
+	}
+	return p[i].seq < p[j].seq
+}
+
+func (p *pendingAuctions) Push(x interface{}) {
+	*p = append(*p, x.(*queuedAuction))
+}
+
+func (p *pendingAuctions) Pop() interface{} {
+	old := *p
+	n := len(old)
+	auction := old[n-1]
+	*p = old[:n-1]
+	return auction
+}
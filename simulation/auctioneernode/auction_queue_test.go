@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/runtime-schema/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auction queue", func() {
+	var queue *auctionQueue
+
+	newAuctionRequest := func(instanceGuid string, priority int) auctiontypes.StartAuctionRequest {
+		return auctiontypes.StartAuctionRequest{
+			LRPStartAuction: models.LRPStartAuction{InstanceGuid: instanceGuid},
+			Priority:        priority,
+		}
+	}
+
+	submit := func(instanceGuid string, priority int) string {
+		id, err := queue.Submit(newAuctionRequest(instanceGuid, priority))
+		Ω(err).ShouldNot(HaveOccurred())
+		return id
+	}
+
+	next := func() string {
+		_, auctionRequest := queue.Next()
+		return auctionRequest.LRPStartAuction.InstanceGuid
+	}
+
+	BeforeEach(func() {
+		queue = newAuctionQueue(10, time.Hour)
+	})
+
+	Describe("ordering", func() {
+		It("should hand out the highest priority first", func() {
+			submit("low", 0)
+			submit("high", 5)
+			submit("negative", -1)
+			submit("middle", 1)
+
+			Ω([]string{next(), next(), next(), next()}).Should(Equal([]string{"high", "middle", "low", "negative"}))
+		})
+
+		It("should hand out auctions of the same priority in the order they were submitted", func() {
+			submit("first", 1)
+			submit("second", 1)
+			submit("urgent", 2)
+			submit("third", 1)
+
+			Ω([]string{next(), next(), next(), next()}).Should(Equal([]string{"urgent", "first", "second", "third"}))
+		})
+
+		It("should take the priority from the request", func() {
+			id := submit("high", 5)
+
+			status, ok := queue.Status(id)
+			Ω(ok).Should(BeTrue())
+			Ω(status.Priority).Should(Equal(5))
+		})
+
+		It("should list the in-flight auctions, then the pending ones in the order they will be held", func() {
+			first := submit("first", 0)
+			urgent := submit("urgent", 2)
+			second := submit("second", 0)
+			Ω(next()).Should(Equal("urgent"))
+
+			ids := []string{}
+			for _, status := range queue.Unfinished() {
+				ids = append(ids, status.ID)
+			}
+			Ω(ids).Should(Equal([]string{urgent, first, second}))
+		})
+	})
+
+	Describe("status", func() {
+		It("should move an auction from pending to in-flight to done", func() {
+			id := submit("red", 0)
+
+			status, ok := queue.Status(id)
+			Ω(ok).Should(BeTrue())
+			Ω(status.ID).Should(Equal(id))
+			Ω(status.State).Should(Equal(auctionPending))
+			Ω(status.Result).Should(BeNil())
+
+			nextID, _ := queue.Next()
+			Ω(nextID).Should(Equal(id))
+			status, _ = queue.Status(id)
+			Ω(status.State).Should(Equal(auctionInFlight))
+			Ω(status.Result).Should(BeNil())
+
+			queue.Finish(id, auctiontypes.StartAuctionResult{Winner: "rep-1"}, nil)
+			status, _ = queue.Status(id)
+			Ω(status.State).Should(Equal(auctionDone))
+			Ω(status.Result.Winner).Should(Equal("rep-1"))
+			Ω(status.Error).Should(BeEmpty())
+			Ω(queue.Unfinished()).Should(BeEmpty())
+		})
+
+		It("should report why a finished auction failed", func() {
+			id := submit("red", 0)
+			queue.Next()
+
+			queue.Finish(id, auctiontypes.StartAuctionResult{}, auctiontypes.InsufficientResources)
+			status, _ := queue.Status(id)
+			Ω(status.State).Should(Equal(auctionDone))
+			Ω(status.Error).Should(Equal(auctiontypes.InsufficientResources.Error()))
+		})
+
+		It("should forget a finished auction once the retention period is over", func() {
+			queue = newAuctionQueue(10, 50*time.Millisecond)
+			id := submit("red", 0)
+			queue.Next()
+			queue.Finish(id, auctiontypes.StartAuctionResult{}, errors.New("boom"))
+
+			_, ok := queue.Status(id)
+			Ω(ok).Should(BeTrue())
+			Eventually(func() bool {
+				_, ok := queue.Status(id)
+				return ok
+			}).Should(BeFalse())
+		})
+
+		It("should not know auctions that were never submitted", func() {
+			_, ok := queue.Status("unknown")
+			Ω(ok).Should(BeFalse())
+
+			queue.Finish("unknown", auctiontypes.StartAuctionResult{}, nil)
+			_, ok = queue.Status("unknown")
+			Ω(ok).Should(BeFalse())
+		})
+	})
+
+	Describe("depth", func() {
+		It("should turn submissions away once maxDepth auctions are pending", func() {
+			queue = newAuctionQueue(2, time.Hour)
+			submit("first", 0)
+			submit("second", 0)
+
+			_, err := queue.Submit(newAuctionRequest("third", 0))
+			Ω(err).Should(Equal(QueueFull))
+
+			//in-flight auctions no longer count against the depth
+			queue.Next()
+			submit("third", 0)
+		})
+
+		It("should block Next until an auction is submitted", func() {
+			instanceGuids := make(chan string)
+			go func() {
+				instanceGuids <- next()
+			}()
+
+			Consistently(instanceGuids).ShouldNot(Receive())
+
+			submit("red", 0)
+			Eventually(instanceGuids).Should(Receive(Equal("red")))
+		})
+	})
+})
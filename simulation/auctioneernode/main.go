@@ -12,6 +12,7 @@ import (
 	"github.com/cloudfoundry-incubator/auction/auctionrunner"
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
 	"github.com/cloudfoundry-incubator/auction/communication/nats/auction_nats_client"
+	"github.com/cloudfoundry-incubator/auction/metrics"
 	"github.com/cloudfoundry-incubator/cf-lager"
 	"github.com/cloudfoundry/yagnats"
 )
@@ -20,10 +21,15 @@ var natsAddrs = flag.String("natsAddrs", "", "nats server addresses")
 var timeout = flag.Duration("timeout", 500*time.Millisecond, "timeout for nats responses")
 var runTimeout = flag.Duration("runTimeout", 10*time.Second, "timeout for run to respond")
 var maxConcurrent = flag.Int("maxConcurrent", 1000, "number of concurrent auctions to hold")
+var maxQueueDepth = flag.Int("maxQueueDepth", 10000, "number of queued auctions to accept before turning submissions away")
+var queueRetention = flag.Duration("queueRetention", 10*time.Minute, "how long the result of a queued auction can be polled for")
 var httpAddr = flag.String("httpAddr", "0.0.0.0:48710", "http address to listen on")
 
 var errorResponse = []byte("error")
 
+// why an auction failed, as auctiondistributor reads it back
+const auctionErrorHeader = "X-Auction-Error"
+
 func main() {
 	flag.Parse()
 
@@ -58,10 +64,72 @@ func main() {
 		log.Fatalln("no rep client:", err)
 	}
 
+	//one runner for every request, so resubmitted instances are recognised
+	auctionRunner := auctionrunner.New(repClient)
+
 	semaphore := make(chan bool, *maxConcurrent)
 
+	//queued auctions share the concurrency limit with the synchronous ones
+	queue := newAuctionQueue(*maxQueueDepth, *queueRetention)
+	for i := 0; i < *maxConcurrent; i++ {
+		go func() {
+			for {
+				id, auctionRequest := queue.Next()
+				semaphore <- true
+				auctionResult, err := auctionRunner.RunLRPStartAuction(auctionRequest)
+				<-semaphore
+				queue.Finish(id, auctionResult, err)
+			}
+		}()
+	}
+
+	http.HandleFunc("/auctions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(queue.Unfinished())
+
+		case "POST":
+			var auctionRequest auctiontypes.StartAuctionRequest
+			err := json.NewDecoder(r.Body).Decode(&auctionRequest)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			id, err := queue.Submit(auctionRequest)
+			if err == QueueFull {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(err.Error()))
+				return
+			}
+
+			status, _ := queue.Status(id)
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(status)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	http.HandleFunc("/auctions/", func(w http.ResponseWriter, r *http.Request) {
+		status, ok := queue.Status(strings.TrimPrefix(r.URL.Path, "/auctions/"))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(status)
+	})
+
 	http.HandleFunc("/start-auction", func(w http.ResponseWriter, r *http.Request) {
-		semaphore <- true
+		select {
+		case semaphore <- true:
+		case <-r.Context().Done():
+			return
+		}
 		defer func() {
 			<-semaphore
 		}()
@@ -73,14 +141,40 @@ func main() {
 			return
 		}
 
-		auctionResult, _ := auctionrunner.New(repClient).RunLRPStartAuction(auctionRequest)
+		auctionResult, err := auctionRunner.RunLRPStartAuctionWithContext(r.Context(), auctionRequest)
+		writeResult(w, auctionResult, err)
+	})
 
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(auctionResult)
+	http.HandleFunc("/start-auction-dry-run", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case semaphore <- true:
+		case <-r.Context().Done():
+			return
+		}
+		defer func() {
+			<-semaphore
+		}()
+
+		var auctionRequest auctiontypes.StartAuctionRequest
+		err := json.NewDecoder(r.Body).Decode(&auctionRequest)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		//never reserve or run: only ask for bids
+		auctionRequest.DryRun = true
+
+		auctionResult, err := auctionRunner.RunLRPStartAuctionWithContext(r.Context(), auctionRequest)
+		writeResult(w, auctionResult, err)
 	})
 
 	http.HandleFunc("/stop-auction", func(w http.ResponseWriter, r *http.Request) {
-		semaphore <- true
+		select {
+		case semaphore <- true:
+		case <-r.Context().Done():
+			return
+		}
 		defer func() {
 			<-semaphore
 		}()
@@ -92,13 +186,68 @@ func main() {
 			return
 		}
 
-		auctionResult, _ := auctionrunner.New(repClient).RunLRPStopAuction(auctionRequest)
+		auctionResult, err := auctionRunner.RunLRPStopAuctionWithContext(r.Context(), auctionRequest)
+		writeResult(w, auctionResult, err)
+	})
 
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(auctionResult)
+	http.HandleFunc("/start-auction-batch", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case semaphore <- true:
+		case <-r.Context().Done():
+			return
+		}
+		defer func() {
+			<-semaphore
+		}()
+
+		var auctionRequest auctiontypes.StartAuctionBatchRequest
+		err := json.NewDecoder(r.Body).Decode(&auctionRequest)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		auctionResults, err := auctionRunner.RunLRPStartAuctionBatchWithContext(r.Context(), auctionRequest)
+		writeResult(w, auctionResults, err)
 	})
 
+	http.Handle("/metrics", metrics.Default)
+
 	fmt.Println("auctioneering")
 
 	panic(http.ListenAndServe(*httpAddr, nil))
 }
+
+// writeResult answers with the result of an auction under a status that says how it went.
+// A failed auction still answers with its result, and with its error in the auctionErrorHeader.
+func writeResult(w http.ResponseWriter, result interface{}, err error) {
+	if err != nil {
+		w.Header().Set(auctionErrorHeader, err.Error())
+	}
+	w.WriteHeader(statusFor(err))
+	json.NewEncoder(w).Encode(result)
+}
+
+func statusFor(err error) int {
+	switch err.(type) {
+	case nil:
+		return http.StatusOK
+	case auctionrunner.UnknownAlgorithmError, auctionrunner.DryRunNotSupportedError, auctionrunner.UnknownStrategyError, auctionrunner.UnknownStopPolicyError, auctionrunner.BatchRuleNotSupportedError:
+		return http.StatusBadRequest
+	}
+
+	switch err {
+	case auctiontypes.AuctionInProgress:
+		return http.StatusConflict
+	case auctiontypes.AuctionDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case auctiontypes.ConstraintNotSatisfied:
+		return http.StatusUnprocessableEntity
+	case auctiontypes.NothingToStop:
+		return http.StatusNotFound
+	case auctiontypes.InsufficientResources, auctiontypes.RepBusy, auctiontypes.RepDraining, auctiontypes.AuctionCancelled:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/auction/auctionrunner"
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auction results", func() {
+	write := func(err error) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		writeResult(recorder, auctiontypes.StartAuctionResult{Winner: "rep-1"}, err)
+		return recorder
+	}
+
+	It("should answer a placed auction with 200 and its result", func() {
+		recorder := write(nil)
+		Ω(recorder.Code).Should(Equal(http.StatusOK))
+		Ω(recorder.Header().Get(auctionErrorHeader)).Should(BeEmpty())
+
+		var result auctiontypes.StartAuctionResult
+		Ω(json.Unmarshal(recorder.Body.Bytes(), &result)).Should(Succeed())
+		Ω(result.Winner).Should(Equal("rep-1"))
+	})
+
+	It("should tell a timed-out auction apart from one nobody could place", func() {
+		Ω(write(auctiontypes.AuctionDeadlineExceeded).Code).Should(Equal(http.StatusGatewayTimeout))
+		Ω(write(auctiontypes.ConstraintNotSatisfied).Code).Should(Equal(http.StatusUnprocessableEntity))
+		Ω(write(auctiontypes.InsufficientResources).Code).Should(Equal(http.StatusServiceUnavailable))
+		Ω(write(auctiontypes.AuctionInProgress).Code).Should(Equal(http.StatusConflict))
+		Ω(write(auctiontypes.NothingToStop).Code).Should(Equal(http.StatusNotFound))
+		Ω(write(auctionrunner.UnknownAlgorithmError{Algorithm: "best_guess"}).Code).Should(Equal(http.StatusBadRequest))
+		Ω(write(auctionrunner.BatchRuleNotSupportedError{Rule: "Preemption"}).Code).Should(Equal(http.StatusBadRequest))
+		Ω(write(errors.New("boom")).Code).Should(Equal(http.StatusInternalServerError))
+	})
+
+	It("should answer a failed auction with its result and its error", func() {
+		recorder := write(auctiontypes.AuctionDeadlineExceeded)
+		Ω(recorder.Header().Get(auctionErrorHeader)).Should(Equal(auctiontypes.AuctionDeadlineExceeded.Error()))
+
+		var result auctiontypes.StartAuctionResult
+		Ω(json.Unmarshal(recorder.Body.Bytes(), &result)).Should(Succeed())
+		Ω(result.Winner).Should(Equal("rep-1"))
+	})
+})
@@ -50,6 +50,10 @@ func (client *InprocessClient) TotalResources(repGuid string) auctiontypes.Resou
 	return client.reps[repGuid].TotalResources()
 }
 
+func (client *InprocessClient) Zone(repGuid string) string {
+	return client.reps[repGuid].Zone()
+}
+
 func (client *InprocessClient) SimulatedInstances(repGuid string) []auctiontypes.SimulatedInstance {
 	return client.reps[repGuid].SimulatedInstances()
 }
@@ -58,6 +62,14 @@ func (client *InprocessClient) SetSimulatedInstances(repGuid string, instances [
 	client.reps[repGuid].SetSimulatedInstances(instances)
 }
 
+func (client *InprocessClient) SetFailsToRun(repGuid string, failsToRun bool) {
+	err := client.reps[repGuid].SetFailsToRun(failsToRun)
+	if err != nil {
+		//test only, so panic is OK
+		panic(err)
+	}
+}
+
 func (client *InprocessClient) Reset(repGuid string) {
 	client.reps[repGuid].Reset()
 }
@@ -77,11 +89,10 @@ func (client *InprocessClient) startAuctionBid(repGuid string, startAuctionInfo
 
 	bid, err := client.reps[repGuid].BidForStartAuction(startAuctionInfo)
 	if err != nil {
-		result.Error = err.Error()
-		return
+		bid.Error = err.Error()
 	}
 
-	result.Bid = bid
+	result = bid
 	return
 }
 
@@ -98,14 +109,12 @@ func (client *InprocessClient) stopScore(repGuid string, auctionInfo auctiontype
 		return
 	}
 
-	bid, instanceGuids, err := client.reps[repGuid].BidForStopAuction(auctionInfo)
+	bid, err := client.reps[repGuid].BidForStopAuction(auctionInfo)
 	if err != nil {
-		result.Error = err.Error()
-		return
+		bid.Error = err.Error()
 	}
 
-	result.InstanceGuids = instanceGuids
-	result.Bid = bid
+	result = bid
 }
 
 func (client *InprocessClient) BidForStopAuction(representatives []string, stopAuctionInfo auctiontypes.StopAuctionInfo) auctiontypes.StopAuctionBids {
@@ -151,11 +160,10 @@ func (client *InprocessClient) reserveAndRecastScore(repGuid string, startAuctio
 
 	bid, err := client.reps[repGuid].RebidThenTentativelyReserve(startAuctionInfo)
 	if err != nil {
-		result.Error = err.Error()
-		return
+		bid.Error = err.Error()
 	}
 
-	result.Bid = bid
+	result = bid
 	return
 }
 
@@ -173,6 +181,86 @@ func (client *InprocessClient) RebidThenTentativelyReserve(repGuids []string, st
 	return results
 }
 
+func (client *InprocessClient) batchStartAuctionBid(repGuid string, startAuctionInfos []auctiontypes.StartAuctionInfo, c chan auctiontypes.BatchStartAuctionBid) {
+	result := auctiontypes.BatchStartAuctionBid{
+		Rep: repGuid,
+	}
+	defer func() {
+		c <- result
+	}()
+
+	if client.beSlowAndPossiblyTimeout(repGuid) {
+		result.Error = "timeout"
+		return
+	}
+
+	bid, err := client.reps[repGuid].BidForBatchStartAuction(startAuctionInfos)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	result = bid
+	return
+}
+
+func (client *InprocessClient) BidForBatchStartAuction(repGuids []string, startAuctionInfos []auctiontypes.StartAuctionInfo) auctiontypes.BatchStartAuctionBids {
+	c := make(chan auctiontypes.BatchStartAuctionBid)
+	for _, repGuid := range repGuids {
+		go client.batchStartAuctionBid(repGuid, startAuctionInfos, c)
+	}
+
+	results := auctiontypes.BatchStartAuctionBids{}
+	for _ = range repGuids {
+		results = append(results, <-c)
+	}
+
+	return results
+}
+
+func (client *InprocessClient) reserveBatch(repGuid string, startAuctionInfos []auctiontypes.StartAuctionInfo, c chan auctiontypes.StartAuctionReservations) {
+	results := auctiontypes.StartAuctionReservations{}
+	defer func() {
+		c <- results
+	}()
+
+	if client.beSlowAndPossiblyTimeout(repGuid) {
+		for _, startAuctionInfo := range startAuctionInfos {
+			results = append(results, auctiontypes.StartAuctionReservation{
+				Rep:          repGuid,
+				InstanceGuid: startAuctionInfo.InstanceGuid,
+				Error:        "timedout",
+			})
+		}
+		return
+	}
+
+	for i, err := range client.reps[repGuid].TentativelyReserveBatch(startAuctionInfos) {
+		result := auctiontypes.StartAuctionReservation{
+			Rep:          repGuid,
+			InstanceGuid: startAuctionInfos[i].InstanceGuid,
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+}
+
+func (client *InprocessClient) TentativelyReserveBatch(startAuctionInfosByRepGuid map[string][]auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionReservations {
+	c := make(chan auctiontypes.StartAuctionReservations)
+	for repGuid, startAuctionInfos := range startAuctionInfosByRepGuid {
+		go client.reserveBatch(repGuid, startAuctionInfos, c)
+	}
+
+	results := auctiontypes.StartAuctionReservations{}
+	for _ = range startAuctionInfosByRepGuid {
+		results = append(results, <-c...)
+	}
+
+	return results
+}
+
 func (client *InprocessClient) ReleaseReservation(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) {
 	c := make(chan bool)
 	for _, repGuid := range repGuids {
@@ -188,14 +276,21 @@ func (client *InprocessClient) ReleaseReservation(repGuids []string, startAuctio
 	}
 }
 
-func (client *InprocessClient) Run(repGuid string, startAuctionInfo models.LRPStartAuction) {
+func (client *InprocessClient) Run(repGuid string, startAuctionInfo models.LRPStartAuction) error {
+	client.beSlowAndPossiblyTimeout(repGuid)
+
+	return client.reps[repGuid].Run(startAuctionInfo)
+}
+
+func (client *InprocessClient) Stop(repGuid string, stopInstance models.StopLRPInstance) error {
 	client.beSlowAndPossiblyTimeout(repGuid)
 
-	client.reps[repGuid].Run(startAuctionInfo)
+	return client.reps[repGuid].Stop(stopInstance)
 }
 
-func (client *InprocessClient) Stop(repGuid string, stopInstance models.StopLRPInstance) {
+func (client *InprocessClient) SetDraining(repGuid string, draining bool) error {
 	client.beSlowAndPossiblyTimeout(repGuid)
 
-	client.reps[repGuid].Stop(stopInstance)
+	client.reps[repGuid].SetDraining(draining)
+	return nil
 }
@@ -6,6 +6,7 @@ ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=0.2 -maxConcurrent=20
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_best -maxBiddingPool=0.2 -maxConcurrent=20
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=0.2 -maxConcurrent=20
+ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=0.2 -maxConcurrent=20
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=0.2 -maxConcurrent=20
 
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=1.0 -maxConcurrent=20
@@ -13,6 +14,7 @@ ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=1.0 -maxConcurrent=20
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_best -maxBiddingPool=1.0 -maxConcurrent=20
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=1.0 -maxConcurrent=20
+ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=1.0 -maxConcurrent=20
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=1.0 -maxConcurrent=20
 
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=0.2 -maxConcurrent=100
@@ -20,6 +22,7 @@ ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=0.2 -maxConcurrent=100
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_best -maxBiddingPool=0.2 -maxConcurrent=100
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=0.2 -maxConcurrent=100
+ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=0.2 -maxConcurrent=100
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=0.2 -maxConcurrent=100
 
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=0.2 -maxConcurrent=1000
@@ -27,6 +30,7 @@ ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=0.2 -maxConcurrent=1000
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_best -maxBiddingPool=0.2 -maxConcurrent=1000
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=0.2 -maxConcurrent=1000
+ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=0.2 -maxConcurrent=1000
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=0.2 -maxConcurrent=1000
 
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=1.0 -maxConcurrent=100
@@ -34,6 +38,7 @@ ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=1.0 -maxConcurrent=100
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_best -maxBiddingPool=1.0 -maxConcurrent=100
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=1.0 -maxConcurrent=100
+ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=1.0 -maxConcurrent=100
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=1.0 -maxConcurrent=100
 
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=1.0 -maxConcurrent=1000
@@ -41,4 +46,5 @@ ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=all_
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=1.0 -maxConcurrent=1000
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_best -maxBiddingPool=1.0 -maxConcurrent=1000
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=1.0 -maxConcurrent=1000
+ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=1.0 -maxConcurrent=1000
 ginkgo -- -communicationMode=ketchup-nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=1.0 -maxConcurrent=1000
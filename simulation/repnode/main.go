@@ -4,11 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/auction/auctionrep"
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
 	auction_nats_server "github.com/cloudfoundry-incubator/auction/communication/nats/auction_nats_server"
+	"github.com/cloudfoundry-incubator/auction/metrics"
 	"github.com/cloudfoundry-incubator/auction/simulation/simulationrepdelegate"
 	"github.com/cloudfoundry-incubator/cf-lager"
 	"github.com/cloudfoundry/yagnats"
@@ -20,7 +24,17 @@ var memoryMB = flag.Int("memoryMB", 100, "total available memory in MB")
 var diskMB = flag.Int("diskMB", 100, "total available disk in MB")
 var containers = flag.Int("containers", 100, "total available containers")
 var repGuid = flag.String("repGuid", "", "rep-guid")
+var zone = flag.String("zone", "", "the zone the rep lives in")
+var resources = flag.String("resources", "", "comma-separated further resources the rep has, as name=quantity (e.g. cpu_millis=4000,ports=50)")
+var overcommit = flag.String("overcommit", "", "comma-separated overcommit factors, as name=factor (e.g. memory_mb=1.5,disk_mb=2)")
+var attributes = flag.String("attributes", "", "comma-separated attributes the rep advertises (e.g. stack=lucid64,has-ssd)")
+var reservationTTL = flag.Duration("reservationTTL", 0, "how long a reservation is held without being run, 0 to hold it forever")
+var bidScorer = flag.String("bidScorer", "default", "how the rep scores its bids, one of "+strings.Join(auctionrep.BidScorers(), ", "))
+var stopBidScorer = flag.String("stopBidScorer", "", "how the rep scores its stop bids, one of "+strings.Join(auctionrep.StopBidScorers(), ", ")+"; empty to keep instances on the reps its bid scorer finds least loaded")
+var maxReservations = flag.Int("maxReservations", 0, "the most reservations the rep holds at once, 0 for no limit")
+var maxInFlightBids = flag.Int("maxInFlightBids", 0, "the most start bids and reservations the rep handles at once, 0 for no limit")
 var natsAddrs = flag.String("natsAddrs", "", "nats server addresses")
+var metricsAddr = flag.String("metricsAddr", "", "http address to serve /metrics on, empty to not serve them")
 
 func main() {
 	flag.Parse()
@@ -33,12 +47,38 @@ func main() {
 		panic("need nats addr")
 	}
 
-	repDelegate := simulationrepdelegate.New(auctiontypes.Resources{
-		MemoryMB:   *memoryMB,
-		DiskMB:     *diskMB,
-		Containers: *containers,
-	})
-	rep := auctionrep.New(*repGuid, repDelegate)
+	repDelegate := simulationrepdelegate.New(repResources(), *zone, repAttributes())
+	scorer, err := auctionrep.LookupBidScorer(*bidScorer)
+	if err != nil {
+		panic(err)
+	}
+
+	config := auctionrep.Config{
+		ReservationTTL: *reservationTTL,
+		Scorer:         scorer,
+		Overcommit:     repOvercommit(),
+		Limits: auctionrep.Limits{
+			MaxReservations: *maxReservations,
+			MaxInFlightBids: *maxInFlightBids,
+		},
+	}
+
+	if *stopBidScorer != "" {
+		config.StopScorer, err = auctionrep.LookupStopBidScorer(*stopBidScorer)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	rep := auctionrep.New(*repGuid, repDelegate, config)
+
+	if *reservationTTL != 0 {
+		go reapExpiredReservations(rep)
+	}
+
+	if *metricsAddr != "" {
+		go serveMetrics()
+	}
 
 	if *natsAddrs != "" {
 		client := yagnats.NewClient()
@@ -70,3 +110,79 @@ func main() {
 
 	select {}
 }
+
+func serveMetrics() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Default)
+	log.Fatalln("metrics server exited:", http.ListenAndServe(*metricsAddr, mux))
+}
+
+func reapExpiredReservations(rep *auctionrep.AuctionRep) {
+	for _ = range time.Tick(*reservationTTL / 2) {
+		reaped, err := rep.ReapExpiredReservations()
+		if err != nil {
+			log.Println("failed to reap reservations:", err)
+			continue
+		}
+		for _, startAuctionInfo := range reaped {
+			log.Println("reaped expired reservation for", startAuctionInfo.InstanceGuid)
+		}
+	}
+}
+
+func repResources() auctiontypes.Resources {
+	total := auctiontypes.Resources{
+		auctiontypes.MemoryMB:   *memoryMB,
+		auctiontypes.DiskMB:     *diskMB,
+		auctiontypes.Containers: *containers,
+	}
+
+	for name, quantity := range namedValues(*resources) {
+		n, err := strconv.Atoi(quantity)
+		if err != nil {
+			panic(err)
+		}
+		total[name] = n
+	}
+
+	return total
+}
+
+func repOvercommit() auctiontypes.OvercommitFactors {
+	factors := auctiontypes.OvercommitFactors{}
+	for name, factor := range namedValues(*overcommit) {
+		f, err := strconv.ParseFloat(factor, 64)
+		if err != nil {
+			panic(err)
+		}
+		factors[name] = f
+	}
+
+	return factors
+}
+
+// parses name=value,name=value
+func namedValues(list string) map[string]string {
+	values := map[string]string{}
+	if list == "" {
+		return values
+	}
+
+	for _, pair := range strings.Split(list, ",") {
+		nameAndValue := strings.SplitN(pair, "=", 2)
+		if len(nameAndValue) != 2 {
+			panic("expected name=value, got " + pair)
+		}
+		values[nameAndValue[0]] = nameAndValue[1]
+	}
+
+	return values
+}
+
+func repAttributes() []string {
+	if *attributes == "" {
+		return nil
+	}
+
+	return strings.Split(*attributes, ",")
+}
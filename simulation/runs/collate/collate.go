@@ -13,6 +13,7 @@ func main() {
 	algorithms := []string{
 		"random",
 		"pick_best",
+		"pick_two",
 		// "pick_among_best",
 		"reserve_n_best",
 		// "all_reserve",
@@ -40,16 +40,20 @@ const Remote = "remote"
 //these are const because they are fixed on ketchup
 const numAuctioneers = 10
 const numReps = 100
+const numZones = 2
 
 var repResources = auctiontypes.Resources{
-	MemoryMB:   100.0,
-	DiskMB:     100.0,
-	Containers: 100,
+	auctiontypes.MemoryMB:   100.0,
+	auctiontypes.DiskMB:     100.0,
+	auctiontypes.Containers: 100,
 }
 
 var maxConcurrent int
 
 var timeout time.Duration
+var bidScorer string
+var stopBidScorer string
+var repConfig auctionrep.Config
 var runTimeout time.Duration
 var auctionDistributor *auctiondistributor.AuctionDistributor
 
@@ -69,10 +73,18 @@ func init() {
 	flag.StringVar(&auctioneerMode, "auctioneerMode", "inprocess", "one of inprocess, remote")
 	flag.DurationVar(&timeout, "timeout", 500*time.Millisecond, "timeout when waiting for responses from remote calls")
 	flag.DurationVar(&runTimeout, "runTimeout", 10*time.Second, "timeout when waiting for the run command to respond")
+	flag.DurationVar(&(repConfig.ReservationTTL), "reservationTTL", 0, "how long reps hold reservations that are never run, 0 to hold them forever")
+	flag.StringVar(&bidScorer, "bidScorer", "default", "how reps score their bids, one of "+strings.Join(auctionrep.BidScorers(), ", "))
+	flag.StringVar(&stopBidScorer, "stopBidScorer", "", "how reps score their stop bids, one of "+strings.Join(auctionrep.StopBidScorers(), ", ")+"; empty to keep instances on the reps their bid scorer finds least loaded")
+	flag.IntVar(&(repConfig.Limits.MaxReservations), "maxReservations", 0, "the most reservations a rep holds at once, 0 for no limit")
+	flag.IntVar(&(repConfig.Limits.MaxInFlightBids), "maxInFlightBids", 0, "the most start bids and reservations a rep handles at once, 0 for no limit")
 
-	flag.StringVar(&(auctionrunner.DefaultStartAuctionRules.Algorithm), "algorithm", auctionrunner.DefaultStartAuctionRules.Algorithm, "the auction algorithm to use")
+	flag.StringVar(&(auctionrunner.DefaultStartAuctionRules.Algorithm), "algorithm", auctionrunner.DefaultStartAuctionRules.Algorithm, "the auction algorithm to use, one of "+strings.Join(auctionrunner.StartAuctionAlgorithms(), ", "))
 	flag.IntVar(&(auctionrunner.DefaultStartAuctionRules.MaxRounds), "maxRounds", auctionrunner.DefaultStartAuctionRules.MaxRounds, "the maximum number of rounds per auction")
 	flag.Float64Var(&(auctionrunner.DefaultStartAuctionRules.MaxBiddingPoolFraction), "maxBiddingPoolFraction", auctionrunner.DefaultStartAuctionRules.MaxBiddingPoolFraction, "the maximum number of participants in the pool")
+	flag.IntVar(&(auctionrunner.DefaultStartAuctionRules.NumChoices), "numChoices", auctionrunner.DefaultStartAuctionRules.NumChoices, "the number of reps sampled per round by pick_two")
+	flag.StringVar(&(auctionrunner.DefaultStartAuctionRules.Strategy), "strategy", auctionrunner.DefaultStartAuctionRules.Strategy, "where to place instances, spread (the default) or pack")
+	flag.DurationVar(&(auctionrunner.DefaultStartAuctionRules.MaxDuration), "maxDuration", auctionrunner.DefaultStartAuctionRules.MaxDuration, "the maximum duration of an auction, 0 for no limit")
 
 	flag.IntVar(&maxConcurrent, "maxConcurrent", 20, "the maximum number of concurrent auctions to run")
 
@@ -88,19 +100,35 @@ var _ = BeforeSuite(func() {
 	fmt.Printf("Running in %s communicationMode\n", communicationMode)
 	fmt.Printf("Running in %s auctioneerMode\n", auctioneerMode)
 
+	if !isRegisteredAlgorithm(auctionrunner.DefaultStartAuctionRules.Algorithm) {
+		panic(fmt.Sprintf("unknown algorithm: %s (registered: %s)", auctionrunner.DefaultStartAuctionRules.Algorithm, strings.Join(auctionrunner.StartAuctionAlgorithms(), ", ")))
+	}
+
+	var err error
+	repConfig.Scorer, err = auctionrep.LookupBidScorer(bidScorer)
+	if err != nil {
+		panic(err)
+	}
+
+	if stopBidScorer != "" {
+		repConfig.StopScorer, err = auctionrep.LookupStopBidScorer(stopBidScorer)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	startReport()
 
 	sessionsToTerminate = []*gexec.Session{}
 	hosts := []string{}
 	switch communicationMode {
 	case InProcess:
-		client, repGuids = buildInProcessReps()
+		client, repGuids = buildInProcessReps(numReps, repConfig)
 		if auctioneerMode == Remote {
 			panic("it doesn't make sense to use remote auctioneers when the reps are in-process")
 		}
 	case NATS:
 		natsAddrs := startNATS()
-		var err error
 
 		natsLogger := lager.NewLogger("test")
 		natsLogger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
@@ -149,7 +177,28 @@ var _ = AfterSuite(func() {
 	}
 })
 
-func buildInProcessReps() (auctiontypes.SimulationRepPoolClient, []string) {
+func isRegisteredAlgorithm(algorithm string) bool {
+	for _, registered := range auctionrunner.StartAuctionAlgorithms() {
+		if registered == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+func buildInProcessReps(numReps int, config auctionrep.Config) (auctiontypes.SimulationRepPoolClient, []string) {
+	resources := make([]auctiontypes.Resources, numReps)
+	configs := make([]auctionrep.Config, numReps)
+	for i := range resources {
+		resources[i] = repResources
+		configs[i] = config
+	}
+
+	return buildInProcessRepsWithResources(resources, configs)
+}
+
+// one rep with each of the given resources, configured by the config at the same index (the zero Config if there is none)
+func buildInProcessRepsWithResources(resources []auctiontypes.Resources, configs []auctionrep.Config) (auctiontypes.SimulationRepPoolClient, []string) {
 	inprocess.LatencyMin = 1 * time.Millisecond
 	inprocess.LatencyMax = 2 * time.Millisecond
 	inprocess.Timeout = 50 * time.Millisecond
@@ -157,18 +206,39 @@ func buildInProcessReps() (auctiontypes.SimulationRepPoolClient, []string) {
 	repGuids := []string{}
 	repMap := map[string]*auctionrep.AuctionRep{}
 
-	for i := 0; i < numReps; i++ {
+	for i := range resources {
 		repGuid := util.NewGuid("REP")
 		repGuids = append(repGuids, repGuid)
 
-		repDelegate := simulationrepdelegate.New(repResources)
-		repMap[repGuid] = auctionrep.New(repGuid, repDelegate)
+		var config auctionrep.Config
+		if i < len(configs) {
+			config = configs[i]
+		}
+
+		repDelegate := simulationrepdelegate.New(resources[i], zoneForRep(i), attributesForRep(i))
+		repMap[repGuid] = auctionrep.New(repGuid, repDelegate, config)
 	}
 
 	client := inprocess.New(repMap)
 	return client, repGuids
 }
 
+func zoneForRep(i int) string {
+	return fmt.Sprintf("z%d", i%numZones+1)
+}
+
+// every fourth rep runs the newer stack
+func stackForRep(i int) string {
+	if i%4 == 0 {
+		return "trusty64"
+	}
+	return "lucid64"
+}
+
+func attributesForRep(i int) []string {
+	return []string{auctiontypes.StackAttribute(stackForRep(i))}
+}
+
 func startNATS() string {
 	natsPort := 5222 + GinkgoParallelNode()
 	natsAddrs := []string{fmt.Sprintf("127.0.0.1:%d", natsPort)}
@@ -190,9 +260,16 @@ func launchExternalReps(communicationFlag string, communicationValue string) []s
 			repNodeBinary,
 			"-repGuid", repGuid,
 			communicationFlag, communicationValue,
-			"-memoryMB", fmt.Sprintf("%d", repResources.MemoryMB),
-			"-diskMB", fmt.Sprintf("%d", repResources.DiskMB),
-			"-containers", fmt.Sprintf("%d", repResources.Containers),
+			"-memoryMB", fmt.Sprintf("%d", repResources[auctiontypes.MemoryMB]),
+			"-diskMB", fmt.Sprintf("%d", repResources[auctiontypes.DiskMB]),
+			"-containers", fmt.Sprintf("%d", repResources[auctiontypes.Containers]),
+			"-zone", zoneForRep(i),
+			"-attributes", strings.Join(attributesForRep(i), ","),
+			"-reservationTTL", fmt.Sprintf("%s", repConfig.ReservationTTL),
+			"-bidScorer", bidScorer,
+			"-stopBidScorer", stopBidScorer,
+			"-maxReservations", fmt.Sprintf("%d", repConfig.Limits.MaxReservations),
+			"-maxInFlightBids", fmt.Sprintf("%d", repConfig.Limits.MaxInFlightBids),
 		)
 
 		sess, err := gexec.Start(serverCmd, GinkgoWriter, GinkgoWriter)
@@ -287,7 +364,7 @@ func ketchupNATSClient() auctiontypes.SimulationRepPoolClient {
 
 func startReport() {
 	reportName = fmt.Sprintf("./runs/%s_%s_pool%.1f_conc%d.svg", auctionrunner.DefaultStartAuctionRules.Algorithm, communicationMode, auctionrunner.DefaultStartAuctionRules.MaxBiddingPoolFraction, maxConcurrent)
-	svgReport = visualization.StartSVGReport(reportName, 2, 3)
+	svgReport = visualization.StartSVGReport(reportName, 2, 4)
 	svgReport.DrawHeader(communicationMode, auctionrunner.DefaultStartAuctionRules, maxConcurrent)
 }
 
@@ -1,8 +1,21 @@
 package simulation_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudfoundry-incubator/auction/auctionrep"
 	"github.com/cloudfoundry-incubator/auction/auctionrunner"
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+	"github.com/cloudfoundry-incubator/auction/metrics"
+	"github.com/cloudfoundry-incubator/auction/simulation/simulationrepdelegate"
 	"github.com/cloudfoundry-incubator/auction/simulation/visualization"
 	"github.com/cloudfoundry-incubator/auction/util"
 	"github.com/cloudfoundry-incubator/runtime-schema/models"
@@ -10,130 +23,2072 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var _ = Ω
+var _ = Ω
+
+var _ = Describe("Auction", func() {
+	var initialDistributions map[int][]auctiontypes.SimulatedInstance
+
+	newSimulatedInstance := func(processGuid string, index int, memoryMB int) auctiontypes.SimulatedInstance {
+		return auctiontypes.SimulatedInstance{
+			ProcessGuid:  processGuid,
+			InstanceGuid: util.NewGuid("INS"),
+			Index:        index,
+			MemoryMB:     memoryMB,
+			DiskMB:       1,
+		}
+	}
+
+	generateUniqueSimulatedInstances := func(numInstances int, index int, memoryMB int) []auctiontypes.SimulatedInstance {
+		instances := []auctiontypes.SimulatedInstance{}
+		for i := 0; i < numInstances; i++ {
+			instances = append(instances, newSimulatedInstance(util.NewGrayscaleGuid("AAA"), index, memoryMB))
+		}
+		return instances
+	}
+
+	generateSimulatedInstancesForProcessGuid := func(processGuid string, numInstances int, index int, memoryMB int) []auctiontypes.SimulatedInstance {
+		instances := []auctiontypes.SimulatedInstance{}
+		for i := 0; i < numInstances; i++ {
+			instances = append(instances, newSimulatedInstance(processGuid, index, memoryMB))
+		}
+		return instances
+	}
+
+	newLRPStartAuction := func(processGuid string, memoryMB int) models.LRPStartAuction {
+		return models.LRPStartAuction{
+			ProcessGuid:  processGuid,
+			InstanceGuid: util.NewGuid("INS"),
+			MemoryMB:     memoryMB,
+			DiskMB:       1,
+			Index:        0,
+		}
+	}
+
+	generateUniqueLRPStartAuctions := func(numInstances int, memoryMB int) []models.LRPStartAuction {
+		instances := []models.LRPStartAuction{}
+		for i := 0; i < numInstances; i++ {
+			instances = append(instances, newLRPStartAuction(util.NewGrayscaleGuid("BBB"), memoryMB))
+		}
+		return instances
+	}
+
+	randomSVGColor := func() string {
+		return []string{"purple", "red", "cyan", "teal", "gray", "blue", "pink", "green", "lime", "orange", "lightseagreen", "brown"}[util.R.Intn(12)]
+	}
+
+	generateLRPStartAuctionsWithRandomSVGColors := func(numInstances int, memoryMB int) []models.LRPStartAuction {
+		instances := []models.LRPStartAuction{}
+		for i := 0; i < numInstances; i++ {
+			instances = append(instances, newLRPStartAuction(randomSVGColor(), memoryMB))
+		}
+		return instances
+	}
+
+	generateLRPStartAuctionsForProcessGuid := func(numInstances int, processGuid string, memoryMB int) []models.LRPStartAuction {
+		instances := []models.LRPStartAuction{}
+		for i := 0; i < numInstances; i++ {
+			instances = append(instances, newLRPStartAuction(processGuid, memoryMB))
+		}
+		return instances
+	}
+
+	BeforeEach(func() {
+		util.ResetGuids()
+		initialDistributions = map[int][]auctiontypes.SimulatedInstance{}
+	})
+
+	JustBeforeEach(func() {
+		for index, simulatedInstances := range initialDistributions {
+			client.SetSimulatedInstances(repGuids[index], simulatedInstances)
+		}
+	})
+
+	Describe("Experiments", func() {
+		Context("Cold start scenario", func() {
+			nexec := []int{25, 100}
+			n1apps := []int{1800, 7000}
+			n2apps := []int{200, 1000}
+			n4apps := []int{50, 200}
+			for i := range nexec {
+				i := i
+				Context("with variable memory requirements between apps", func() {
+					It("should distribute evenly", func() {
+						instances := []models.LRPStartAuction{}
+
+						instances = append(instances, generateUniqueLRPStartAuctions(n1apps[i]/2, 1)...)
+						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n1apps[i]/2, 1)...)
+						instances = append(instances, generateUniqueLRPStartAuctions(n2apps[i]/2, 2)...)
+						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n2apps[i]/2, 2)...)
+						instances = append(instances, generateUniqueLRPStartAuctions(n4apps[i]/2, 4)...)
+						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n4apps[i]/2, 4)...)
+
+						permutedInstances := make([]models.LRPStartAuction, len(instances))
+						for i, index := range util.R.Perm(len(instances)) {
+							permutedInstances[i] = instances[index]
+						}
+						report := auctionDistributor.HoldAuctionsFor(
+							"Cold start with variable memory requirements between apps",
+							nexec[i],
+							permutedInstances,
+							repGuids[:nexec[i]],
+							auctionrunner.DefaultStartAuctionRules,
+						)
+
+						visualization.PrintReport(
+							client,
+							report.AuctionResults,
+							repGuids[:nexec[i]],
+							report.AuctionDuration,
+							auctionrunner.DefaultStartAuctionRules,
+						)
+
+						svgReport.DrawReportCard(i, 0, report)
+						reports = append(reports, report)
+					})
+				})
+			}
+		})
+
+		Context("Batched cold start scenario", func() {
+			nexec := []int{25, 100}
+			n1apps := []int{1800, 7000}
+			n2apps := []int{200, 1000}
+			n4apps := []int{50, 200}
+			for i := range nexec {
+				i := i
+				Context("with variable memory requirements between apps", func() {
+					It("should distribute evenly", func() {
+						instances := []models.LRPStartAuction{}
+
+						instances = append(instances, generateUniqueLRPStartAuctions(n1apps[i]/2, 1)...)
+						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n1apps[i]/2, 1)...)
+						instances = append(instances, generateUniqueLRPStartAuctions(n2apps[i]/2, 2)...)
+						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n2apps[i]/2, 2)...)
+						instances = append(instances, generateUniqueLRPStartAuctions(n4apps[i]/2, 4)...)
+						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n4apps[i]/2, 4)...)
+
+						report := auctionDistributor.HoldBatchAuctionsFor(
+							"Batched cold start with variable memory requirements between apps",
+							nexec[i],
+							instances,
+							repGuids[:nexec[i]],
+							auctionrunner.DefaultStartAuctionRules,
+							len(instances),
+						)
+
+						visualization.PrintReport(
+							client,
+							report.AuctionResults,
+							repGuids[:nexec[i]],
+							report.AuctionDuration,
+							auctionrunner.DefaultStartAuctionRules,
+						)
+
+						svgReport.DrawReportCard(i, 3, report)
+						reports = append(reports, report)
+
+						Ω(report.NMissingInstances()).Should(BeZero())
+						Ω(report.ExcessZoneFactorStats().Max).Should(BeNumerically("<=", 1.0))
+					})
+				})
+			}
+		})
+
+		Context("Constrained start scenario", func() {
+			nexec := 25
+
+			Context("when only some reps run the requested stack", func() {
+				It("should only place instances on those reps", func() {
+					instances := generateLRPStartAuctionsWithRandomSVGColors(200, 1)
+					for i := range instances {
+						instances[i].Stack = "trusty64"
+					}
+
+					report := auctionDistributor.HoldAuctionsFor(
+						"Cold start on a stack only some reps run",
+						nexec,
+						instances,
+						repGuids[:nexec],
+						auctionrunner.DefaultStartAuctionRules,
+					)
+
+					Ω(report.NMissingInstances()).Should(BeZero())
+					for i, repGuid := range repGuids[:nexec] {
+						if stackForRep(i) != "trusty64" {
+							Ω(report.InstancesByRep[repGuid]).Should(BeEmpty())
+						}
+					}
+				})
+			})
+
+			Context("when no rep has a required attribute", func() {
+				It("should fail the start auction on the constraint rather than on resources", func() {
+					_, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+						LRPStartAuction:    newLRPStartAuction("red", 1),
+						RepGuids:           repGuids[:nexec],
+						Rules:              auctionrunner.DefaultStartAuctionRules,
+						RequiredAttributes: []string{"has-ssd"},
+					})
+					Ω(err).Should(Equal(auctiontypes.ConstraintNotSatisfied))
+				})
+
+				It("should give up on the batched instance after a single round", func() {
+					lrpStartAuction := newLRPStartAuction("red", 1)
+					results, err := auctionrunner.New(client).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+						LRPStartAuctions:                []models.LRPStartAuction{lrpStartAuction},
+						RepGuids:                        repGuids[:nexec],
+						Rules:                           auctionrunner.DefaultStartAuctionRules,
+						RequiredAttributesByProcessGuid: map[string][]string{"red": {"has-ssd"}},
+					})
+					Ω(err).Should(Equal(auctiontypes.ConstraintNotSatisfied))
+					Ω(results).Should(HaveLen(1))
+					Ω(results[0].Winner).Should(BeEmpty())
+					Ω(results[0].NumRounds).Should(Equal(1))
+				})
+
+				It("should tell the batched instances nobody may run from those there was no room for", func() {
+					unsatisfiable := newLRPStartAuction("red", 1)
+					unplaceable := newLRPStartAuction("blue", 1<<20)
+					placeable := newLRPStartAuction("green", 1)
+					before := scrapeMetrics()
+
+					results, err := auctionrunner.New(client).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+						LRPStartAuctions:                []models.LRPStartAuction{unsatisfiable, unplaceable, placeable},
+						RepGuids:                        repGuids[:nexec],
+						Rules:                           auctionrunner.DefaultStartAuctionRules,
+						RequiredAttributesByProcessGuid: map[string][]string{"red": {"has-ssd"}},
+					})
+					Ω(err).Should(Equal(auctiontypes.ConstraintNotSatisfied))
+					Ω(results[0].Error).Should(Equal(auctiontypes.ConstraintNotSatisfied.Error()))
+					Ω(results[1].Error).Should(Equal(auctiontypes.InsufficientResources.Error()))
+					Ω(results[2].Error).Should(BeEmpty())
+					Ω(results[2].Winner).ShouldNot(BeEmpty())
+
+					after := scrapeMetrics()
+					delta := func(series string) float64 {
+						return after[series] - before[series]
+					}
+					Ω(delta(`auction_start_auctions_failed_total{algorithm="batch",reason="constraint-not-satisfied"}`)).Should(Equal(1.0))
+					Ω(delta(`auction_start_auctions_failed_total{algorithm="batch",reason="insufficient-resources"}`)).Should(Equal(1.0))
+				})
+			})
+
+			It("should refuse batches asking for what only single auctions do", func() {
+				for _, rule := range []string{"Preemption", "RecordHistory", "ExplainBids"} {
+					rules := auctionrunner.DefaultStartAuctionRules
+					rules.Preemption = rule == "Preemption"
+					rules.RecordHistory = rule == "RecordHistory"
+					rules.ExplainBids = rule == "ExplainBids"
+
+					lrpStartAuction := newLRPStartAuction("red", 1)
+					results, err := auctionrunner.New(client).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+						LRPStartAuctions: []models.LRPStartAuction{lrpStartAuction},
+						RepGuids:         repGuids[:nexec],
+						Rules:            rules,
+					})
+					Ω(err).Should(Equal(auctionrunner.BatchRuleNotSupportedError{Rule: rule}))
+					Ω(results[0].Winner).Should(BeEmpty())
+				}
+
+				for _, repGuid := range repGuids[:nexec] {
+					Ω(client.SimulatedInstances(repGuid)).Should(BeEmpty())
+				}
+			})
+		})
+
+		Context("Affinity scenario", func() {
+			nexec := 25
+
+			antiAffinityRequests := func(numInstances int, processGuid string, awayFrom string) []auctiontypes.StartAuctionRequest {
+				auctionRequests := []auctiontypes.StartAuctionRequest{}
+				for _, lrpStartAuction := range generateLRPStartAuctionsForProcessGuid(numInstances, processGuid, 1) {
+					auctionRequests = append(auctionRequests, auctiontypes.StartAuctionRequest{
+						LRPStartAuction: lrpStartAuction,
+						RepGuids:        repGuids[:nexec],
+						Rules:           auctionrunner.DefaultStartAuctionRules,
+						AffinityRules:   []auctiontypes.AffinityRule{{ProcessGuid: awayFrom, Anti: true, Hard: true}},
+					})
+				}
+				return auctionRequests
+			}
+
+			repsRunningBoth := func(processGuidA string, processGuidB string) []string {
+				reps := []string{}
+				for _, repGuid := range repGuids[:nexec] {
+					runsA, runsB := false, false
+					for _, instance := range client.SimulatedInstances(repGuid) {
+						runsA = runsA || instance.ProcessGuid == processGuidA
+						runsB = runsB || instance.ProcessGuid == processGuidB
+					}
+					if runsA && runsB {
+						reps = append(reps, repGuid)
+					}
+				}
+				return reps
+			}
+
+			Context("when instances must never share a rep with another process", func() {
+				BeforeEach(func() {
+					for i := 0; i < 12; i++ {
+						initialDistributions[i] = generateSimulatedInstancesForProcessGuid("blue", 1, i, 1)
+					}
+				})
+
+				It("should never place them alongside it", func() {
+					report := auctionDistributor.HoldAuctionRequestsFor(
+						"Hard anti-affinity against a process on half the reps",
+						nexec,
+						antiAffinityRequests(300, "red", "blue"),
+						repGuids[:nexec],
+					)
+
+					Ω(report.NMissingInstances()).Should(BeZero())
+					Ω(repsRunningBoth("red", "blue")).Should(BeEmpty())
+				})
+			})
+
+			Context("when two processes refuse to share a rep and are auctioned concurrently", func() {
+				It("should never place them together", func() {
+					auctionRequests := antiAffinityRequests(100, "red", "purple")
+					auctionRequests = append(auctionRequests, antiAffinityRequests(100, "purple", "red")...)
+					permutedRequests := make([]auctiontypes.StartAuctionRequest, len(auctionRequests))
+					for i, index := range util.R.Perm(len(auctionRequests)) {
+						permutedRequests[i] = auctionRequests[index]
+					}
+
+					auctionDistributor.HoldAuctionRequestsFor(
+						"Mutual hard anti-affinity",
+						nexec,
+						permutedRequests,
+						repGuids[:nexec],
+					)
+
+					Ω(repsRunningBoth("red", "purple")).Should(BeEmpty())
+				})
+
+				It("should never place them together in a batch", func() {
+					instances := generateLRPStartAuctionsForProcessGuid(100, "red", 1)
+					instances = append(instances, generateLRPStartAuctionsForProcessGuid(100, "purple", 1)...)
+
+					auctionrunner.New(client).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+						LRPStartAuctions: instances,
+						RepGuids:         repGuids[:nexec],
+						Rules:            auctionrunner.DefaultStartAuctionRules,
+						AffinityRulesByProcessGuid: map[string][]auctiontypes.AffinityRule{
+							"red":    {{ProcessGuid: "purple", Anti: true, Hard: true}},
+							"purple": {{ProcessGuid: "red", Anti: true, Hard: true}},
+						},
+					})
+					Ω(repsRunningBoth("red", "purple")).Should(BeEmpty())
+				})
+			})
+
+			Context("when instances prefer to be placed with another process", func() {
+				BeforeEach(func() {
+					initialDistributions[3] = generateSimulatedInstancesForProcessGuid("blue", 1, 0, 1)
+				})
+
+				It("should favor the rep running it", func() {
+					rules := auctionrunner.DefaultStartAuctionRules
+					rules.MaxBiddingPoolFraction = 1.0
+
+					result, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+						LRPStartAuction: newLRPStartAuction("red", 1),
+						RepGuids:        repGuids[:nexec],
+						Rules:           rules,
+						AffinityRules:   []auctiontypes.AffinityRule{{ProcessGuid: "blue"}},
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(result.Winner).Should(Equal(repGuids[3]))
+				})
+			})
+		})
+
+		Context("Preemption scenario", func() {
+			nexec := 10
+
+			BeforeEach(func() {
+				for i := 0; i < nexec; i++ {
+					initialDistributions[i] = generateUniqueSimulatedInstances(repResources[auctiontypes.Containers], 0, 1)
+				}
+			})
+
+			startAuction := func(priority int, preemption bool) (auctiontypes.StartAuctionResult, error) {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Preemption = preemption
+
+				return auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+					Priority:        priority,
+				})
+			}
+
+			Context("when preemption is off", func() {
+				It("should fail the high-priority instance", func() {
+					_, err := startAuction(10, false)
+					Ω(err).Should(Equal(auctiontypes.InsufficientResources))
+				})
+			})
+
+			Context("when preemption is on", func() {
+				It("should evict a lower-priority instance to place the high-priority one", func() {
+					for i := 0; i < 5; i++ {
+						result, err := startAuction(10, true)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(result.Evicted).Should(HaveLen(1))
+
+						instanceGuids := []string{}
+						for _, instance := range client.SimulatedInstances(result.Winner) {
+							instanceGuids = append(instanceGuids, instance.InstanceGuid)
+						}
+						Ω(instanceGuids).Should(ContainElement(result.LRPStartAuction.InstanceGuid))
+						Ω(instanceGuids).ShouldNot(ContainElement(result.Evicted[0].InstanceGuid))
+					}
+				})
+
+				It("should not evict instances of the same priority", func() {
+					result, err := startAuction(0, true)
+					Ω(err).Should(Equal(auctiontypes.InsufficientResources))
+					Ω(result.Evicted).Should(BeEmpty())
+				})
+
+				numInstances := func() int {
+					n := 0
+					for _, repGuid := range repGuids[:nexec] {
+						n += len(client.SimulatedInstances(repGuid))
+					}
+					return n
+				}
+
+				It("should evict nobody when the winner will not reserve", func() {
+					rules := auctionrunner.DefaultStartAuctionRules
+					rules.Preemption = true
+
+					result, err := auctionrunner.New(newRefusingClient(client)).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+						LRPStartAuction: newLRPStartAuction("red", 1),
+						RepGuids:        repGuids[:nexec],
+						Rules:           rules,
+						Priority:        10,
+					})
+					Ω(err).Should(HaveOccurred())
+					Ω(result.Winner).Should(BeEmpty())
+					Ω(result.Evicted).Should(BeEmpty())
+					Ω(numInstances()).Should(Equal(nexec * repResources[auctiontypes.Containers]))
+				})
+
+				It("should evict nobody when the auction runs out of time once the winner has reserved", func() {
+					rules := auctionrunner.DefaultStartAuctionRules
+					rules.Preemption = true
+
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+					interrupting := newInterruptingClient(client, cancel)
+
+					result, err := auctionrunner.New(interrupting).RunLRPStartAuctionWithContext(ctx, auctiontypes.StartAuctionRequest{
+						LRPStartAuction: newLRPStartAuction("red", 1),
+						RepGuids:        repGuids[:nexec],
+						Rules:           rules,
+						Priority:        10,
+					})
+					Ω(err).Should(Equal(auctiontypes.AuctionCancelled))
+					Ω(interrupting.Interruptions()).Should(Equal(1))
+					Ω(result.Evicted).Should(BeEmpty())
+					Ω(numInstances()).Should(Equal(nexec * repResources[auctiontypes.Containers]))
+					for _, repGuid := range repGuids[:nexec] {
+						for _, instance := range client.SimulatedInstances(repGuid) {
+							Ω(instance.Reserved).Should(BeFalse())
+						}
+					}
+				})
+
+				Context("when a rep holds another auction's reservation", func() {
+					BeforeEach(func() {
+						//room for one more instance, and that one the largest
+						initialDistributions[0] = generateUniqueSimulatedInstances(repResources[auctiontypes.Containers]-1, 0, 0)
+					})
+
+					It("should only evict running instances", func() {
+						blue := newLRPStartAuction("blue", 1)
+						bids := client.RebidThenTentativelyReserve(repGuids[:1], auctiontypes.NewStartAuctionInfoFromLRPStartAuction(blue))
+						Ω(bids[0].Error).Should(BeEmpty())
+
+						rules := auctionrunner.DefaultStartAuctionRules
+						rules.Preemption = true
+						result, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+							LRPStartAuction: newLRPStartAuction("red", 1),
+							RepGuids:        repGuids[:1],
+							Rules:           rules,
+							Priority:        10,
+						})
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(result.Evicted).Should(HaveLen(1))
+						Ω(result.Evicted[0].InstanceGuid).ShouldNot(Equal(blue.InstanceGuid))
+
+						Ω(client.Run(repGuids[0], blue)).Should(Succeed())
+					})
+				})
+			})
+		})
+
+		Context("Algorithm registry scenario", func() {
+			nexec := 10
+
+			startAuction := func(algorithm string, dryRun bool) (auctiontypes.StartAuctionResult, error) {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Algorithm = algorithm
+
+				return auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+					DryRun:          dryRun,
+				})
+			}
+
+			numInstances := func() int {
+				numInstances := 0
+				for _, repGuid := range repGuids[:nexec] {
+					numInstances += len(client.SimulatedInstances(repGuid))
+				}
+				return numInstances
+			}
+
+			It("should list every registered algorithm, sorted", func() {
+				Ω(auctionrunner.StartAuctionAlgorithms()).Should(Equal([]string{
+					"all_rebid",
+					"all_reserve",
+					delegatingAlgorithm,
+					"pick_among_best",
+					"pick_best",
+					"pick_two",
+					"random",
+					"reserve_n_best",
+				}))
+			})
+
+			It("should run an algorithm as soon as it is registered", func() {
+				delegated := atomic.LoadInt32(&delegatedAuctions)
+
+				result, err := startAuction(delegatingAlgorithm, false)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).ShouldNot(BeEmpty())
+				Ω(atomic.LoadInt32(&delegatedAuctions)).Should(Equal(delegated + 1))
+				Ω(client.SimulatedInstances(result.Winner)).Should(HaveLen(1))
+
+				result, err = startAuction(delegatingAlgorithm, true)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).ShouldNot(BeEmpty())
+				Ω(numInstances()).Should(Equal(1))
+			})
+
+			It("should refuse algorithms it does not know, placing nothing", func() {
+				_, err := startAuction("best_guess", false)
+				Ω(err).Should(Equal(auctionrunner.UnknownAlgorithmError{Algorithm: "best_guess"}))
+
+				_, err = startAuction("best_guess", true)
+				Ω(err).Should(Equal(auctionrunner.UnknownAlgorithmError{Algorithm: "best_guess"}))
+
+				Ω(numInstances()).Should(BeZero())
+			})
+
+			It("should refuse to register an algorithm twice, or a dry run for an algorithm it does not know", func() {
+				Ω(func() {
+					auctionrunner.RegisterStartAuctionAlgorithm("pick_best", delegateToPickBest)
+				}).Should(Panic())
+				Ω(func() {
+					auctionrunner.RegisterDryRunStartAuctionAlgorithm("best_guess", dryRunDelegateToPickBest)
+				}).Should(Panic())
+				Ω(auctionrunner.StartAuctionAlgorithms()).ShouldNot(ContainElement("best_guess"))
+			})
+		})
+
+		Context("Pick two scenario", func() {
+			nexec := 10
+
+			BeforeEach(func() {
+				//no two reps bid alike
+				for i := 0; i < nexec; i++ {
+					initialDistributions[i] = generateUniqueSimulatedInstances(5*i, 0, 1)
+				}
+			})
+
+			pickTwo := func(numChoices int) auctiontypes.StartAuctionRules {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Algorithm = "pick_two"
+				rules.NumChoices = numChoices
+				return rules
+			}
+
+			startAuction := func(repPoolClient auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (auctiontypes.StartAuctionResult, error) {
+				return auctionrunner.New(repPoolClient).RunLRPStartAuction(auctionRequest)
+			}
+
+			It("should take the better of two sampled reps, every round", func() {
+				recording := newBidRecordingClient(client)
+				for _, lrpStartAuction := range generateUniqueLRPStartAuctions(20, 1) {
+					result, err := startAuction(recording, auctiontypes.StartAuctionRequest{
+						LRPStartAuction: lrpStartAuction,
+						RepGuids:        repGuids[:nexec],
+						Rules:           pickTwo(2),
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(result.NumRounds).Should(Equal(1))
+				}
+
+				rounds := recording.Rounds()
+				Ω(rounds).Should(HaveLen(20))
+
+				sampled := map[string]bool{}
+				for _, round := range rounds {
+					Ω(round.Bids).Should(HaveLen(2))
+					Ω(round.Bids[0].Rep).ShouldNot(Equal(round.Bids[1].Rep))
+
+					better := round.Bids[0]
+					if round.Bids[1].Bid < better.Bid {
+						better = round.Bids[1]
+					}
+					Ω(round.Reserved).Should(Equal([]string{better.Rep}))
+
+					for _, bid := range round.Bids {
+						Ω(repGuids[:nexec]).Should(ContainElement(bid.Rep))
+						sampled[bid.Rep] = true
+					}
+				}
+
+				//a fresh sample every round, not the same two reps
+				Ω(len(sampled)).Should(BeNumerically(">", 2))
+			})
+
+			It("should sample as many reps as the rules choose", func() {
+				for _, numChoices := range []int{0, 1, 3} {
+					recording := newBidRecordingClient(client)
+					_, err := startAuction(recording, auctiontypes.StartAuctionRequest{
+						LRPStartAuction: newLRPStartAuction("red", 1),
+						RepGuids:        repGuids[:nexec],
+						Rules:           pickTwo(numChoices),
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+
+					expected := numChoices
+					if numChoices == 0 {
+						expected = 2
+					}
+					Ω(recording.Rounds()[0].Bids).Should(HaveLen(expected), fmt.Sprintf("%d choices", numChoices))
+				}
+			})
+
+			It("should make do with a single rep", func() {
+				recording := newBidRecordingClient(client)
+				result, err := startAuction(recording, auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:1],
+					Rules:           pickTwo(2),
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[0]))
+				Ω(recording.Rounds()[0].Bids.Reps()).Should(Equal(auctiontypes.RepGuids{repGuids[0]}))
+			})
+
+			It("should keep sampling until it finds the only rep that may run the instance", func() {
+				Ω(stackForRep(0)).Should(Equal("trusty64"))
+				lrpStartAuction := newLRPStartAuction("red", 1)
+				lrpStartAuction.Stack = "trusty64"
+
+				recording := newBidRecordingClient(client)
+				result, err := startAuction(recording, auctiontypes.StartAuctionRequest{
+					LRPStartAuction: lrpStartAuction,
+					RepGuids:        repGuids[:4],
+					Rules:           pickTwo(2),
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[0]))
+				for _, round := range recording.Rounds() {
+					if round.Reserved != nil {
+						Ω(round.Reserved).Should(Equal([]string{repGuids[0]}))
+					}
+				}
+			})
+
+			It("should give up on the constraint when no sampled rep is ever a candidate", func() {
+				recording := newBidRecordingClient(client)
+				result, err := startAuction(recording, auctiontypes.StartAuctionRequest{
+					LRPStartAuction:    newLRPStartAuction("red", 1),
+					RepGuids:           repGuids[:nexec],
+					Rules:              pickTwo(2),
+					RequiredAttributes: []string{"has-ssd"},
+				})
+				Ω(err).Should(Equal(auctiontypes.ConstraintNotSatisfied))
+				Ω(result.Winner).Should(BeEmpty())
+
+				Ω(recording.Rounds()).Should(HaveLen(auctionrunner.DefaultStartAuctionRules.MaxRounds))
+				for _, round := range recording.Rounds() {
+					Ω(round.Reserved).Should(BeNil())
+				}
+				for i := 0; i < nexec; i++ {
+					Ω(client.SimulatedInstances(repGuids[i])).Should(HaveLen(len(initialDistributions[i])))
+				}
+			})
+		})
+
+		Context("Deadline scenario", func() {
+			nexec := 10
+
+			rulesFor := func(algorithm string, maxDuration time.Duration) auctiontypes.StartAuctionRules {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Algorithm = algorithm
+				rules.MaxBiddingPoolFraction = 1.0
+				rules.MaxDuration = maxDuration
+				return rules
+			}
+
+			startAuction := func(ctx context.Context, repPoolClient auctiontypes.RepPoolClient, rules auctiontypes.StartAuctionRules) (auctiontypes.StartAuctionResult, error) {
+				return auctionrunner.New(repPoolClient).RunLRPStartAuctionWithContext(ctx, auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+				})
+			}
+
+			startBatch := func(ctx context.Context, repPoolClient auctiontypes.RepPoolClient, rules auctiontypes.StartAuctionRules) ([]auctiontypes.StartAuctionResult, error) {
+				return auctionrunner.New(repPoolClient).RunLRPStartAuctionBatchWithContext(ctx, auctiontypes.StartAuctionBatchRequest{
+					LRPStartAuctions: generateUniqueLRPStartAuctions(20, 1),
+					RepGuids:         repGuids[:nexec],
+					Rules:            rules,
+				})
+			}
+
+			//running or reserved, an abandoned auction should leave none behind
+			numInstances := func() int {
+				numInstances := 0
+				for _, repGuid := range repGuids[:nexec] {
+					numInstances += len(client.SimulatedInstances(repGuid))
+				}
+				return numInstances
+			}
+
+			It("should not ask any rep once the deadline has passed", func() {
+				ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+				defer cancel()
+
+				result, err := startAuction(ctx, client, rulesFor("reserve_n_best", 0))
+				Ω(err).Should(Equal(auctiontypes.AuctionDeadlineExceeded))
+				Ω(result.Winner).Should(BeEmpty())
+				Ω(result.NumCommunications).Should(BeZero())
+
+				results, err := startBatch(ctx, client, rulesFor("reserve_n_best", 0))
+				Ω(err).Should(Equal(auctiontypes.AuctionDeadlineExceeded))
+				for _, result := range results {
+					Ω(result.Winner).Should(BeEmpty())
+					Ω(result.NumCommunications).Should(BeZero())
+				}
+
+				Ω(numInstances()).Should(BeZero())
+			})
+
+			It("should release the reservations it holds when the deadline passes mid-round, with every algorithm", func() {
+				for _, algorithm := range auctionrunner.StartAuctionAlgorithms() {
+					//every reservation takes longer than the whole auction may
+					slow := newInterruptingClient(client, func() { time.Sleep(40 * time.Millisecond) })
+
+					result, err := startAuction(context.Background(), slow, rulesFor(algorithm, 20*time.Millisecond))
+					Ω(err).Should(Equal(auctiontypes.AuctionDeadlineExceeded), algorithm)
+					Ω(result.Winner).Should(BeEmpty(), algorithm)
+					Ω(slow.Interruptions()).Should(BeNumerically(">", 0), algorithm)
+					Ω(numInstances()).Should(BeZero(), algorithm)
+				}
+			})
+
+			It("should release the reservations it holds when cancelled part-way, with every algorithm", func() {
+				for _, algorithm := range auctionrunner.StartAuctionAlgorithms() {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancelling := newInterruptingClient(client, cancel)
+
+					result, err := startAuction(ctx, cancelling, rulesFor(algorithm, 0))
+					Ω(err).Should(Equal(auctiontypes.AuctionCancelled), algorithm)
+					Ω(result.Winner).Should(BeEmpty(), algorithm)
+					Ω(cancelling.Interruptions()).Should(BeNumerically(">", 0), algorithm)
+					Ω(numInstances()).Should(BeZero(), algorithm)
+				}
+			})
+
+			It("should release a batch's reservations when the deadline passes mid-round", func() {
+				slow := newInterruptingClient(client, func() { time.Sleep(40 * time.Millisecond) })
+
+				results, err := startBatch(context.Background(), slow, rulesFor("reserve_n_best", 20*time.Millisecond))
+				Ω(err).Should(Equal(auctiontypes.AuctionDeadlineExceeded))
+				for _, result := range results {
+					Ω(result.Winner).Should(BeEmpty())
+				}
+				Ω(slow.Interruptions()).Should(Equal(1))
+				Ω(numInstances()).Should(BeZero())
+			})
+
+			It("should release a batch's reservations when cancelled part-way", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancelling := newInterruptingClient(client, cancel)
+
+				results, err := startBatch(ctx, cancelling, rulesFor("reserve_n_best", 0))
+				Ω(err).Should(Equal(auctiontypes.AuctionCancelled))
+				for _, result := range results {
+					Ω(result.Winner).Should(BeEmpty())
+				}
+				Ω(cancelling.Interruptions()).Should(Equal(1))
+				Ω(numInstances()).Should(BeZero())
+			})
+		})
+
+		Context("Dry run scenario", func() {
+			nexec := 10
+
+			BeforeEach(func() {
+				for i := 0; i < nexec; i++ {
+					initialDistributions[i] = generateUniqueSimulatedInstances(50, 0, 1)
+				}
+				initialDistributions[6] = generateUniqueSimulatedInstances(10, 0, 1)
+			})
+
+			dryRun := func(algorithm string) (auctiontypes.StartAuctionResult, error) {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Algorithm = algorithm
+				rules.MaxBiddingPoolFraction = 1.0
+
+				return auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+					DryRun:          true,
+				})
+			}
+
+			It("should report where the instance would land without placing it", func() {
+				result, err := dryRun("pick_best")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[6]))
+				Ω(result.RankedBids).Should(HaveLen(nexec))
+				Ω(result.RankedBids[0].Rep).Should(Equal(result.Winner))
+
+				for i := 0; i < nexec; i++ {
+					Ω(client.SimulatedInstances(repGuids[i])).Should(HaveLen(len(initialDistributions[i])))
+				}
+			})
+
+			It("should be supported by every algorithm that asks for bids", func() {
+				for _, algorithm := range auctionrunner.StartAuctionAlgorithms() {
+					_, err := dryRun(algorithm)
+					if algorithm == "random" {
+						Ω(err).Should(Equal(auctionrunner.DryRunNotSupportedError{Algorithm: algorithm}))
+					} else {
+						Ω(err).ShouldNot(HaveOccurred(), algorithm)
+					}
+				}
+
+				for i := 0; i < nexec; i++ {
+					Ω(client.SimulatedInstances(repGuids[i])).Should(HaveLen(len(initialDistributions[i])))
+				}
+			})
+		})
+
+		Context("Explanation scenario", func() {
+			nexec := 10
+
+			BeforeEach(func() {
+				for i := 0; i < nexec; i++ {
+					initialDistributions[i] = generateUniqueSimulatedInstances(50, 0, 1)
+				}
+				initialDistributions[6] = generateUniqueSimulatedInstances(10, 0, 1)
+			})
+
+			It("should explain why the winner won, when asked", func() {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.MaxBiddingPoolFraction = 1.0
+				rules.ExplainBids = true
+
+				result, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[6]))
+
+				explanation := result.Explanation
+				Ω(explanation).ShouldNot(BeNil())
+				Ω(explanation.RemainingResources[auctiontypes.MemoryMB]).Should(Equal(90))
+				Ω(explanation.TotalResources[auctiontypes.MemoryMB]).Should(Equal(100))
+				Ω(explanation.NumInstancesForProcessGuid).Should(Equal(0))
+				Ω(explanation.Usage[auctiontypes.MemoryMB]).Should(BeNumerically("~", 0.1, 1e-9))
+				Ω(explanation.Score).Should(BeNumerically("~", 0.1, 1e-9))
+				Ω(explanation.AffinityPenalty).Should(BeZero())
+				Ω(explanation.EvictionCost).Should(BeZero())
+				Ω(explanation.Constraints).Should(Equal([]auctiontypes.ConstraintResult{
+					{Constraint: "resource " + auctiontypes.Containers, Satisfied: true},
+					{Constraint: "resource " + auctiontypes.DiskMB, Satisfied: true},
+					{Constraint: "resource " + auctiontypes.MemoryMB, Satisfied: true},
+				}))
+			})
+
+			It("should explain refusals constraint by constraint", func() {
+				info := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(newLRPStartAuction("red", 1))
+				info.RequiredAttributes = []string{"has-gpu"}
+				info.AffinityRules = []auctiontypes.AffinityRule{{ProcessGuid: "blue", Hard: true}}
+				info.Explain = true
+
+				bids := client.BidForStartAuction(repGuids[:1], info)
+				Ω(bids).Should(HaveLen(1))
+				Ω(bids[0].Error).Should(Equal(auctiontypes.ConstraintNotSatisfied.Error()))
+				Ω(bids[0].Explanation).ShouldNot(BeNil())
+				Ω(bids[0].Explanation.Constraints[:2]).Should(Equal([]auctiontypes.ConstraintResult{
+					{Constraint: "attribute has-gpu", Satisfied: false},
+					{Constraint: "affinity with blue", Satisfied: false},
+				}))
+			})
+
+			It("should leave bids as they were on the wire unless asked", func() {
+				info := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(newLRPStartAuction("red", 1))
+				payload, err := json.Marshal(info)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(payload)).ShouldNot(ContainSubstring("Explain"))
+
+				bids := client.BidForStartAuction(repGuids[:1], info)
+				Ω(bids).Should(HaveLen(1))
+				Ω(bids[0].Explanation).Should(BeNil())
+				payload, err = json.Marshal(bids[0])
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(payload)).ShouldNot(ContainSubstring("Explanation"))
+			})
+		})
+
+		Context("History scenario", func() {
+			nexec := 10
+
+			BeforeEach(func() {
+				for i := 0; i < nexec; i++ {
+					initialDistributions[i] = generateUniqueSimulatedInstances(repResources[auctiontypes.Containers], 0, 1)
+				}
+				initialDistributions[6] = generateUniqueSimulatedInstances(10, 0, 1)
+			})
+
+			It("should record every round it took to find the one rep with room", func() {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Algorithm = "pick_best"
+				rules.MaxBiddingPoolFraction = 0.2
+				rules.MinBiddingPool = 2
+				rules.MaxRounds = 100
+				rules.RecordHistory = true
+
+				result, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[6]))
+
+				visualization.PrintHistory(result)
+
+				Ω(result.History).Should(HaveLen(result.NumRounds))
+				for i, round := range result.History {
+					Ω(round.Round).Should(Equal(i + 1))
+					Ω(round.Sampled).ShouldNot(BeEmpty())
+					if i < len(result.History)-1 {
+						Ω(round.Abandoned).ShouldNot(BeEmpty())
+						Ω(round.Ran).Should(BeEmpty())
+					}
+				}
+
+				lastRound := result.History[len(result.History)-1]
+				Ω(lastRound.Abandoned).Should(BeEmpty())
+				Ω(lastRound.Ran).Should(Equal(result.Winner))
+				Ω(lastRound.Reserved).Should(ContainElement(result.Winner))
+			})
+
+			It("should not record anything unless asked", func() {
+				result, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           auctionrunner.DefaultStartAuctionRules,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.History).Should(BeEmpty())
+			})
+		})
+
+		Context("Auctioneer crash scenario", func() {
+			//a rep of its own that gives up on reservations quickly; the suite's reps hold theirs as long as they are told to
+			reservationTTL := 500 * time.Millisecond
+			var crashClient auctiontypes.SimulationRepPoolClient
+			var crashRepGuids []string
+
+			BeforeEach(func() {
+				crashClient, crashRepGuids = buildInProcessReps(1, auctionrep.Config{ReservationTTL: reservationTTL})
+				crashClient.SetSimulatedInstances(crashRepGuids[0], generateUniqueSimulatedInstances(repResources[auctiontypes.Containers]-1, 0, 0))
+			})
+
+			//a single round, well within the TTL: does the rep have room right now?
+			startAuction := func(processGuid string) (auctiontypes.StartAuctionResult, error) {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.MaxRounds = 1
+
+				return auctionrunner.New(crashClient).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction(processGuid, 1),
+					RepGuids:        crashRepGuids,
+					Rules:           rules,
+				})
+			}
+
+			reservedInstances := func() []string {
+				reserved := []string{}
+				for _, instance := range crashClient.SimulatedInstances(crashRepGuids[0]) {
+					if instance.Reserved {
+						reserved = append(reserved, instance.InstanceGuid)
+					}
+				}
+				return reserved
+			}
+
+			It("should reclaim the room reserved by an auctioneer that never ran its instance", func() {
+				//the auctioneer reserves the last container, then dies before telling the rep to run
+				abandoned := newLRPStartAuction("red", 1)
+				bids := crashClient.RebidThenTentativelyReserve(crashRepGuids, auctiontypes.NewStartAuctionInfoFromLRPStartAuction(abandoned))
+				Ω(bids[0].Error).Should(BeEmpty())
+				Ω(reservedInstances()).Should(Equal([]string{abandoned.InstanceGuid}))
+
+				_, err := startAuction("blue")
+				Ω(err).Should(Equal(auctiontypes.InsufficientResources))
+
+				var result auctiontypes.StartAuctionResult
+				Eventually(func() error {
+					result, err = startAuction("green")
+					return err
+				}, 3*reservationTTL, reservationTTL/4).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(crashRepGuids[0]))
+
+				instanceGuids := []string{}
+				for _, instance := range crashClient.SimulatedInstances(crashRepGuids[0]) {
+					Ω(instance.Reserved).Should(BeFalse())
+					instanceGuids = append(instanceGuids, instance.InstanceGuid)
+				}
+				Ω(instanceGuids).Should(ContainElement(result.LRPStartAuction.InstanceGuid))
+				Ω(instanceGuids).ShouldNot(ContainElement(abandoned.InstanceGuid))
+			})
+
+			It("should leave a running instance alone, however late its reservation is released", func() {
+				running := newLRPStartAuction("red", 1)
+				runningInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(running)
+				bids := crashClient.RebidThenTentativelyReserve(crashRepGuids, runningInfo)
+				Ω(bids[0].Error).Should(BeEmpty())
+				Ω(crashClient.Run(crashRepGuids[0], running)).Should(Succeed())
+
+				//a duplicate release, then a reaper long past the TTL
+				crashClient.ReleaseReservation(crashRepGuids, runningInfo)
+				time.Sleep(reservationTTL + reservationTTL/2)
+				_, err := startAuction("blue")
+				Ω(err).Should(Equal(auctiontypes.InsufficientResources))
+
+				instanceGuids := []string{}
+				for _, instance := range crashClient.SimulatedInstances(crashRepGuids[0]) {
+					instanceGuids = append(instanceGuids, instance.InstanceGuid)
+				}
+				Ω(instanceGuids).Should(ContainElement(running.InstanceGuid))
+				Ω(reservedInstances()).Should(BeEmpty())
+			})
+		})
+
+		Context("Run failure scenario", func() {
+			nexec := 10
+
+			BeforeEach(func() {
+				//the emptiest rep, and so everyone's favourite, refuses to run anything
+				for i := 1; i < nexec; i++ {
+					initialDistributions[i] = generateUniqueSimulatedInstances(50, 0, 1)
+				}
+				client.SetFailsToRun(repGuids[0], true)
+			})
+
+			startAuction := func(algorithm string) (auctiontypes.StartAuctionResult, error) {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Algorithm = algorithm
+				rules.MaxBiddingPoolFraction = 1.0
+
+				return auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+				})
+			}
+
+			It("should fall back to the next best reserved rep within the round", func() {
+				result, err := startAuction("reserve_n_best")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).ShouldNot(Equal(repGuids[0]))
+				Ω(result.NumRounds).Should(Equal(1))
+				Ω(result.RunFailures).Should(HaveLen(1))
+				Ω(result.RunFailures[0].Rep).Should(Equal(repGuids[0]))
+				Ω(result.RunFailures[0].InstanceGuid).Should(Equal(result.LRPStartAuction.InstanceGuid))
+			})
+
+			It("should fall back to a new round without the failing rep", func() {
+				result, err := startAuction("pick_best")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).ShouldNot(Equal(repGuids[0]))
+				Ω(result.NumRounds).Should(Equal(2))
+				Ω(result.RunFailures).Should(HaveLen(1))
+				Ω(result.RunFailures[0].Rep).Should(Equal(repGuids[0]))
+			})
+
+			It("should place the instance elsewhere with every algorithm", func() {
+				for _, algorithm := range auctionrunner.StartAuctionAlgorithms() {
+					result, err := startAuction(algorithm)
+					Ω(err).ShouldNot(HaveOccurred(), algorithm)
+					Ω(result.Winner).ShouldNot(Equal(repGuids[0]), algorithm)
+					for _, failure := range result.RunFailures {
+						Ω(failure.Rep).Should(Equal(repGuids[0]), algorithm)
+					}
+				}
+
+				Ω(client.SimulatedInstances(repGuids[0])).Should(BeEmpty())
+			})
+
+			It("should place batched instances elsewhere", func() {
+				results, err := auctionrunner.New(client).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+					LRPStartAuctions: generateUniqueLRPStartAuctions(20, 1),
+					RepGuids:         repGuids[:nexec],
+					Rules:            auctionrunner.DefaultStartAuctionRules,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				numFailures := 0
+				for _, result := range results {
+					Ω(result.Winner).ShouldNot(BeEmpty())
+					Ω(result.Winner).ShouldNot(Equal(repGuids[0]))
+					numFailures += len(result.RunFailures)
+				}
+				Ω(numFailures).Should(BeNumerically(">", 0))
+
+				Ω(client.SimulatedInstances(repGuids[0])).Should(BeEmpty())
+			})
+
+			It("should refuse to make a rep that does not simulate fail to run", func() {
+				//hide the simulation behind the plain delegate interface
+				repDelegate := struct {
+					auctiontypes.AuctionRepDelegate
+				}{simulationrepdelegate.New(repResources, zoneForRep(0), nil)}
+
+				rep := auctionrep.New("plain-rep", repDelegate, repConfig)
+				Ω(rep.SetFailsToRun(true)).Should(Equal(auctionrep.NotSimulated))
+			})
+		})
+
+		Context("Observer scenario", func() {
+			nexec := 10
+
+			BeforeEach(func() {
+				//as in the run failure scenario: the favourite rep refuses to run, costing a round
+				for i := 1; i < nexec; i++ {
+					initialDistributions[i] = generateUniqueSimulatedInstances(50, 0, 1)
+				}
+				client.SetFailsToRun(repGuids[0], true)
+			})
+
+			It("should tell every observer of every step of the auction, in order", func() {
+				first, second := newRecordingObserver(), newRecordingObserver()
+
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Algorithm = "pick_best"
+				rules.MaxBiddingPoolFraction = 1.0
+				result, err := auctionrunner.New(client, first, second).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedEvents := []string{
+					"round-started 1",
+					fmt.Sprintf("bids-received %d", nexec),
+					"reservation-attempted [" + repGuids[0] + "]",
+					"ran " + repGuids[0] + " failed",
+					"reservation-released [" + repGuids[0] + "]",
+					"round-abandoned 1",
+					"round-started 2",
+					fmt.Sprintf("bids-received %d", nexec),
+					"reservation-attempted [" + result.Winner + "]",
+					"ran " + result.Winner,
+					"auction-finished " + result.Winner,
+				}
+				Ω(first.Events()).Should(Equal(expectedEvents))
+				Ω(second.Events()).Should(Equal(expectedEvents))
+			})
+
+			It("should tell observers how batched auctions finished", func() {
+				observer := newRecordingObserver()
+				results, err := auctionrunner.New(client, observer).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+					LRPStartAuctions: generateUniqueLRPStartAuctions(3, 1),
+					RepGuids:         repGuids[:nexec],
+					Rules:            auctionrunner.DefaultStartAuctionRules,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				expectedEvents := []string{}
+				for _, result := range results {
+					expectedEvents = append(expectedEvents, "auction-finished "+result.Winner)
+				}
+				Ω(observer.Events()).Should(Equal(expectedEvents))
+			})
+		})
+
+		Context("Tracing scenario", func() {
+			nexec := 10
+
+			It("should send every request of an auction under the trace ID it returns", func() {
+				tracing := newTracingClient(client)
+				runner := auctionrunner.New(tracing)
+
+				traceIDs := map[string]bool{}
+				for i := 0; i < 3; i++ {
+					result, err := runner.RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+						LRPStartAuction: newLRPStartAuction("red", 1),
+						RepGuids:        repGuids[:nexec],
+						Rules:           auctionrunner.DefaultStartAuctionRules,
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(result.TraceID).ShouldNot(BeEmpty())
+					Ω(traceIDs).ShouldNot(HaveKey(result.TraceID))
+					traceIDs[result.TraceID] = true
+
+					Ω(tracing.Requests(result.TraceID)).Should(ContainElement("run " + result.Winner))
+				}
+				Ω(tracing.Requests("")).Should(BeEmpty())
+			})
+
+			It("should trace a batch as one auction", func() {
+				tracing := newTracingClient(client)
+				results, err := auctionrunner.New(tracing).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+					LRPStartAuctions: generateUniqueLRPStartAuctions(3, 1),
+					RepGuids:         repGuids[:nexec],
+					Rules:            auctionrunner.DefaultStartAuctionRules,
+				})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				traceID := results[0].TraceID
+				Ω(traceID).ShouldNot(BeEmpty())
+				for _, result := range results {
+					Ω(result.TraceID).Should(Equal(traceID))
+					Ω(tracing.Requests(traceID)).Should(ContainElement("run " + result.Winner))
+				}
+				Ω(tracing.Requests("")).Should(BeEmpty())
+			})
+		})
+
+		Context("Bid scorer scenario", func() {
+			//which of a pool of reps of its own, alternating between zones, wins an auction when they score with the named scorer (keeping the auction's strategy)
+			winnerWith := func(scorerName string, auctionRequest auctiontypes.StartAuctionRequest, distributions ...[]auctiontypes.SimulatedInstance) int {
+				scorer, err := auctionrep.LookupBidScorer(scorerName)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				scoredClient, scoredRepGuids := buildInProcessReps(len(distributions), auctionrep.Config{Scorer: scorer})
+				for i, instances := range distributions {
+					scoredClient.SetSimulatedInstances(scoredRepGuids[i], instances)
+				}
+
+				auctionRequest.RepGuids = scoredRepGuids
+				strategy := auctionRequest.Rules.Strategy
+				auctionRequest.Rules = auctionrunner.DefaultStartAuctionRules
+				auctionRequest.Rules.Strategy = strategy
+				auctionRequest.Rules.Algorithm = "pick_best"
+				auctionRequest.Rules.MaxBiddingPoolFraction = 1.0
+				result, err := auctionrunner.New(scoredClient).RunLRPStartAuction(auctionRequest)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				for i, repGuid := range scoredRepGuids {
+					if repGuid == result.Winner {
+						return i
+					}
+				}
+				return -1
+			}
+
+			It("should keep the default scorer's bids", func() {
+				info := auctionrep.StartInstanceScoreInfo{
+					RemainingResources:         auctiontypes.Resources{auctiontypes.MemoryMB: 70, auctiontypes.DiskMB: 40, auctiontypes.Containers: 90},
+					TotalResources:             auctiontypes.Resources{auctiontypes.MemoryMB: 100, auctiontypes.DiskMB: 100, auctiontypes.Containers: 100},
+					NumInstancesForProcessGuid: 2,
+				}
+				Ω(auctionrep.DefaultScorer.Score(info)).Should(BeNumerically("~", (0.3+0.6+0.1)/3.0+2, 1e-9))
+				Ω(auctionrep.StartAuctionBid(auctiontypes.StartAuctionInfo{}, info)).Should(Equal(auctionrep.DefaultScorer.Score(info)))
+			})
+
+			It("should turn the usage around when packing", func() {
+				info := auctionrep.StartInstanceScoreInfo{
+					RemainingResources:         auctiontypes.Resources{auctiontypes.MemoryMB: 70, auctiontypes.DiskMB: 40, auctiontypes.Containers: 90},
+					TotalResources:             auctiontypes.Resources{auctiontypes.MemoryMB: 100, auctiontypes.DiskMB: 100, auctiontypes.Containers: 100},
+					NumInstancesForProcessGuid: 2,
+				}
+				packing := auctiontypes.StartAuctionInfo{Strategy: auctiontypes.PackStrategy}
+				Ω(auctionrep.StartAuctionBid(packing, info)).Should(BeNumerically("~", 1-(0.3+0.6+0.1)/3.0+2, 1e-9))
+			})
+
+			It("should place on the rep using less memory when memory is weighted more heavily", func() {
+				//the first rep runs many tiny instances, the second a few memory-hungry ones
+				manyTiny := generateUniqueSimulatedInstances(40, 0, 0)
+				fewHungry := generateUniqueSimulatedInstances(10, 0, 3)
+				auctionRequest := auctiontypes.StartAuctionRequest{LRPStartAuction: newLRPStartAuction("red", 1)}
+
+				Ω(winnerWith("default", auctionRequest, manyTiny, fewHungry)).Should(Equal(1))
+				Ω(winnerWith("memory_weighted", auctionRequest, manyTiny, fewHungry)).Should(Equal(0))
+			})
+
+			It("should pack with the rep's own scorer turned around", func() {
+				//the first rep runs many tiny instances, the second a few memory-hungry ones
+				manyTiny := generateUniqueSimulatedInstances(40, 0, 0)
+				fewHungry := generateUniqueSimulatedInstances(10, 0, 3)
+				packing := func(scorerName string) int {
+					auctionRequest := auctiontypes.StartAuctionRequest{LRPStartAuction: newLRPStartAuction("red", 1)}
+					auctionRequest.Rules.Strategy = auctiontypes.PackStrategy
+					return winnerWith(scorerName, auctionRequest, manyTiny, fewHungry)
+				}
+
+				//fullest by every resource alike, fullest by memory
+				Ω(packing("default")).Should(Equal(0))
+				Ω(packing("memory_weighted")).Should(Equal(1))
+
+				memoryWeighted, err := auctionrep.LookupBidScorer("memory_weighted")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(auctionrep.PackingScorerFor(memoryWeighted)).Should(Equal(auctionrep.PackingScorer{WeightedScorer: memoryWeighted.(auctionrep.WeightedScorer)}))
+				Ω(auctionrep.PackingScorerFor(auctionrep.DefaultScorer)).Should(Equal(auctionrep.DefaultPackingScorer))
+				Ω(auctionrep.PackingScorerFor(auctionrep.DefaultPackingScorer)).Should(Equal(auctionrep.DefaultPackingScorer))
+			})
+
+			It("should rather break a soft affinity rule than colocate when colocation is penalized more heavily", func() {
+				//the first and last reps share a zone: the first runs the preferred process but also an instance of red, the last runs neither;
+				//the busy rep in between keeps the zones even
+				preferredButColocated := append(generateSimulatedInstancesForProcessGuid("blue", 1, 0, 0), generateSimulatedInstancesForProcessGuid("red", 1, 0, 0)...)
+				busy := append(generateUniqueSimulatedInstances(90, 0, 0), generateSimulatedInstancesForProcessGuid("red", 1, 0, 0)...)
+				neither := generateUniqueSimulatedInstances(10, 0, 0)
+				auctionRequest := auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					AffinityRules:   []auctiontypes.AffinityRule{{ProcessGuid: "blue"}},
+				}
+
+				Ω(winnerWith("default", auctionRequest, preferredButColocated, busy, neither)).Should(Equal(0))
+				Ω(winnerWith("colocation_averse", auctionRequest, preferredButColocated, busy, neither)).Should(Equal(2))
+			})
+
+			Context("when batched", func() {
+				var scoredClient auctiontypes.SimulationRepPoolClient
+				var scoredRepGuids []string
+
+				BeforeEach(func() {
+					//two reps of one zone, so zone balancing stays out of it:
+					//the empty first rep packs, so it bids as if it were full; the second is half full and spreads
+					resources := make([]auctiontypes.Resources, numZones+1)
+					for i := range resources {
+						resources[i] = repResources
+					}
+
+					var allRepGuids []string
+					scoredClient, allRepGuids = buildInProcessRepsWithResources(resources, []auctionrep.Config{{Scorer: auctionrep.DefaultPackingScorer}})
+					scoredRepGuids = []string{allRepGuids[0], allRepGuids[numZones]}
+					scoredClient.SetSimulatedInstances(scoredRepGuids[1], generateUniqueSimulatedInstances(50, 0, 1))
+				})
+
+				winners := func(lrpStartAuctions ...models.LRPStartAuction) []string {
+					results, err := auctionrunner.New(scoredClient).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+						LRPStartAuctions: lrpStartAuctions,
+						RepGuids:         scoredRepGuids,
+						Rules:            auctionrunner.DefaultStartAuctionRules,
+					})
+					Ω(err).ShouldNot(HaveOccurred())
+
+					winners := []string{}
+					for _, result := range results {
+						winners = append(winners, result.Winner)
+					}
+					return winners
+				}
+
+				It("should place by each rep's own scorer", func() {
+					Ω(winners(newLRPStartAuction("red", 1))).Should(Equal([]string{scoredRepGuids[1]}))
+				})
+
+				It("should move a rep's score by what was placed on it before", func() {
+					//the second red would be colocated with the first on the half-full rep: the packing rep is better now
+					Ω(winners(newLRPStartAuction("red", 1), newLRPStartAuction("red", 1))).Should(ConsistOf(scoredRepGuids[0], scoredRepGuids[1]))
+				})
+			})
+		})
+
+		Context("Packing scenario", func() {
+			nexec := 20
+			napps := 200
+
+			holdAuctionsWith := func(strategy string) *visualization.Report {
+				for _, repGuid := range repGuids[:nexec] {
+					client.Reset(repGuid)
+				}
+
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Strategy = strategy
+				report := auctionDistributor.HoldAuctionsFor(
+					"Packing scenario ("+strategy+")",
+					nexec,
+					generateUniqueLRPStartAuctions(napps, 1),
+					repGuids[:nexec],
+					rules,
+				)
+
+				visualization.PrintReport(
+					client,
+					report.AuctionResults,
+					repGuids[:nexec],
+					report.AuctionDuration,
+					rules,
+				)
+
+				return report
+			}
+
+			It("should leave more reps idle than spreading does", func() {
+				spread := holdAuctionsWith(auctiontypes.SpreadStrategy)
+				Ω(spread.NMissingInstances()).Should(BeZero())
+
+				packed := holdAuctionsWith(auctiontypes.PackStrategy)
+				Ω(packed.NMissingInstances()).Should(BeZero())
+
+				fmt.Printf("Idle reps: %d spreading (Dist: %.3f), %d packing (Dist: %.3f)\n", spread.NIdleReps(), spread.DistributionScore(), packed.NIdleReps(), packed.DistributionScore())
+				Ω(packed.NIdleReps()).Should(BeNumerically(">", spread.NIdleReps()))
+				Ω(packed.DistributionScore()).Should(BeNumerically(">", spread.DistributionScore()))
+			})
+
+			It("should refuse a strategy it does not know", func() {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Strategy = "scatter"
+				_, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:nexec],
+					Rules:           rules,
+				})
+				Ω(err).Should(Equal(auctionrunner.UnknownStrategyError{Strategy: "scatter"}))
+			})
+		})
+
+		Context("Resource dimensions scenario", func() {
+			//the reps in z1 have 1000 cpu millis, those in z2 have no cpu to speak of
+			var cpuClient auctiontypes.SimulationRepPoolClient
+			var cpuRepGuids []string
+
+			BeforeEach(func() {
+				resources := []auctiontypes.Resources{}
+				for i := 0; i < 4; i++ {
+					if zoneForRep(i) == "z1" {
+						resources = append(resources, repResources.Add(auctiontypes.Resources{auctiontypes.CPUMillis: 1000}))
+					} else {
+						resources = append(resources, repResources)
+					}
+				}
+				cpuClient, cpuRepGuids = buildInProcessRepsWithResources(resources, nil)
+			})
+
+			auctionNeedingCPU := func(cpuMillis int) (auctiontypes.StartAuctionResult, error) {
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.MaxRounds = 5
+				return auctionrunner.New(cpuClient).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        cpuRepGuids,
+					Rules:           rules,
+					Resources:       auctiontypes.Resources{auctiontypes.CPUMillis: cpuMillis},
+				})
+			}
+
+			It("should only place instances where every resource they need is left", func() {
+				winners := map[string]bool{}
+				for i := 0; i < 2; i++ {
+					result, err := auctionNeedingCPU(600)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω([]string{cpuRepGuids[0], cpuRepGuids[2]}).Should(ContainElement(result.Winner))
+					winners[result.Winner] = true
+				}
+				Ω(winners).Should(HaveLen(2))
+
+				_, err := auctionNeedingCPU(600)
+				Ω(err).Should(Equal(auctiontypes.InsufficientResources))
+
+				Ω(cpuClient.TotalResources(cpuRepGuids[0])[auctiontypes.CPUMillis]).Should(Equal(1000))
+			})
+
+			It("should keep the fields resources had before they were a vector on the wire", func() {
+				legacy := struct {
+					DiskMB     int
+					MemoryMB   int
+					Containers int
+				}{DiskMB: 20, MemoryMB: 30, Containers: 40}
+
+				payload, err := json.Marshal(legacy)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				var resources auctiontypes.Resources
+				Ω(json.Unmarshal(payload, &resources)).Should(Succeed())
+				Ω(resources).Should(Equal(auctiontypes.Resources{auctiontypes.DiskMB: 20, auctiontypes.MemoryMB: 30, auctiontypes.Containers: 40}))
+
+				payload, err = json.Marshal(resources.Add(auctiontypes.Resources{auctiontypes.Ports: 5}))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(payload).Should(MatchJSON(`{"DiskMB": 20, "MemoryMB": 30, "Containers": 40, "ports": 5}`))
+
+				legacy.MemoryMB = 0
+				Ω(json.Unmarshal(payload, &legacy)).Should(Succeed())
+				Ω(legacy.MemoryMB).Should(Equal(30))
+			})
+		})
+
+		Context("Overcommit scenario", func() {
+			//the reps in z1 promise half again as much memory as they have
+			var overcommitClient auctiontypes.SimulationRepPoolClient
+			var overcommitRepGuids []string
+
+			BeforeEach(func() {
+				resources := []auctiontypes.Resources{}
+				configs := []auctionrep.Config{}
+				for i := 0; i < 4; i++ {
+					resources = append(resources, repResources)
+					if zoneForRep(i) == "z1" {
+						configs = append(configs, auctionrep.Config{Overcommit: auctiontypes.OvercommitFactors{auctiontypes.MemoryMB: 1.5}})
+					} else {
+						configs = append(configs, auctionrep.Config{})
+					}
+				}
+				overcommitClient, overcommitRepGuids = buildInProcessRepsWithResources(resources, configs)
+			})
+
+			It("should fill the overcommitted reps to their ratio and the others to what they have", func() {
+				runner := auctionrunner.New(overcommitClient)
+				auction := func() error {
+					_, err := runner.RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+						LRPStartAuction: newLRPStartAuction(util.NewGrayscaleGuid("BBB"), 10),
+						RepGuids:        overcommitRepGuids,
+						Rules:           auctionrunner.DefaultStartAuctionRules,
+					})
+					return err
+				}
+
+				//15 instances fit on each overcommitted rep and 10 on the others
+				for i := 0; i < 50; i++ {
+					Ω(auction()).Should(Succeed())
+				}
+				Ω(auction()).Should(Equal(auctiontypes.InsufficientResources))
+
+				for i, repGuid := range overcommitRepGuids {
+					memory := 0
+					for _, instance := range overcommitClient.SimulatedInstances(repGuid) {
+						memory += instance.MemoryMB
+					}
+
+					if zoneForRep(i) == "z1" {
+						Ω(memory).Should(Equal(150))
+						Ω(overcommitClient.TotalResources(repGuid)[auctiontypes.MemoryMB]).Should(Equal(150))
+					} else {
+						Ω(memory).Should(Equal(100))
+						Ω(overcommitClient.TotalResources(repGuid)[auctiontypes.MemoryMB]).Should(Equal(100))
+					}
+					Ω(overcommitClient.TotalResources(repGuid)[auctiontypes.DiskMB]).Should(Equal(100))
+				}
+			})
+		})
+
+		Context("Evacuation scenario", func() {
+			nexec := 10
+			var lrpStartAuctions []models.LRPStartAuction
+
+			BeforeEach(func() {
+				//the first rep runs instances the others have plenty of room for
+				lrpStartAuctions = generateUniqueLRPStartAuctions(20, 1)
+				for _, lrpStartAuction := range lrpStartAuctions {
+					initialDistributions[0] = append(initialDistributions[0], auctiontypes.SimulatedInstance{
+						ProcessGuid:  lrpStartAuction.ProcessGuid,
+						InstanceGuid: lrpStartAuction.InstanceGuid,
+						Index:        lrpStartAuction.Index,
+						MemoryMB:     lrpStartAuction.MemoryMB,
+						DiskMB:       lrpStartAuction.DiskMB,
+					})
+				}
+			})
+
+			evacuate := func(runner auctiontypes.AuctionRunner, pool []string) (auctiontypes.EvacuationResult, error) {
+				return runner.RunEvacuation(auctiontypes.EvacuationRequest{
+					RepGuid:          repGuids[0],
+					LRPStartAuctions: lrpStartAuctions,
+					RepGuids:         pool,
+					Rules:            auctionrunner.DefaultStartAuctionRules,
+				})
+			}
+
+			It("should refuse to bid for new instances while draining", func() {
+				auction := func() error {
+					_, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+						LRPStartAuction: newLRPStartAuction("red", 1),
+						RepGuids:        repGuids[:1],
+						Rules:           auctionrunner.DefaultStartAuctionRules,
+					})
+					return err
+				}
+
+				startAuctionInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(newLRPStartAuction("red", 1))
+
+				Ω(client.SetDraining(repGuids[0], true)).Should(Succeed())
+				Ω(client.BidForStartAuction(repGuids[:1], startAuctionInfo)[0].Error).Should(Equal(auctiontypes.RepDraining.Error()))
+				Ω(client.RebidThenTentativelyReserve(repGuids[:1], startAuctionInfo)[0].Error).Should(Equal(auctiontypes.RepDraining.Error()))
+				Ω(auction()).Should(Equal(auctiontypes.RepDraining))
+
+				Ω(client.SetDraining(repGuids[0], false)).Should(Succeed())
+				Ω(auction()).Should(Succeed())
+			})
+
+			It("should take draining reps for a soft failure, and busy ones for the softer", func() {
+				Ω(client.SetDraining(repGuids[0], true)).Should(Succeed())
+
+				//a draining pool is not out of room, and evicting from it is pointless
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.Preemption = true
+				result, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        repGuids[:1],
+					Rules:           rules,
+				})
+				Ω(err).Should(Equal(auctiontypes.RepDraining))
+				Ω(result.Evicted).Should(BeEmpty())
+
+				//the first rep holds as many reservations as it takes, the second drains
+				mixedClient, mixedRepGuids := buildInProcessRepsWithResources(
+					[]auctiontypes.Resources{repResources, repResources},
+					[]auctionrep.Config{{Limits: auctionrep.Limits{MaxReservations: 1}}},
+				)
+				Ω(mixedClient.RebidThenTentativelyReserve(mixedRepGuids[:1], auctiontypes.NewStartAuctionInfoFromLRPStartAuction(newLRPStartAuction("blue", 1)))[0].Error).Should(BeEmpty())
+				Ω(mixedClient.SetDraining(mixedRepGuids[1], true)).Should(Succeed())
+
+				_, err = auctionrunner.New(mixedClient).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        mixedRepGuids,
+					Rules:           auctionrunner.DefaultStartAuctionRules,
+				})
+				Ω(err).Should(Equal(auctiontypes.RepBusy))
+			})
+
+			It("should stop each moved instance under the trace ID of the auction that moved it", func() {
+				tracing := newTracingClient(client)
+				result, err := evacuate(auctionrunner.New(tracing), repGuids[:nexec])
+				Ω(err).ShouldNot(HaveOccurred())
+
+				traceIDs := map[string]bool{}
+				for _, evacuation := range result.Instances {
+					Ω(evacuation.TraceID).ShouldNot(BeEmpty())
+					Ω(traceIDs).ShouldNot(HaveKey(evacuation.TraceID))
+					traceIDs[evacuation.TraceID] = true
+
+					requests := tracing.Requests(evacuation.TraceID)
+					Ω(requests).Should(ContainElement("run " + evacuation.Winner))
+					Ω(requests[len(requests)-1]).Should(Equal("stop " + repGuids[0]))
+				}
+				Ω(tracing.Requests("")).Should(BeEmpty())
+			})
+
+			It("should move every instance to another rep, then stop it on the draining one", func() {
+				observer := newRecordingObserver()
+				result, err := evacuate(auctionrunner.New(client, observer), repGuids[:nexec])
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.RepGuid).Should(Equal(repGuids[0]))
+				Ω(result.Instances).Should(HaveLen(len(lrpStartAuctions)))
+
+				expectedEvents := []string{}
+				for i, evacuation := range result.Instances {
+					Ω(evacuation.LRPStartAuction).Should(Equal(lrpStartAuctions[i]))
+					Ω(evacuation.Winner).ShouldNot(BeEmpty())
+					Ω(evacuation.Winner).ShouldNot(Equal(repGuids[0]))
+					Ω(evacuation.Stopped).Should(BeTrue())
+					Ω(evacuation.Error).Should(BeEmpty())
+					expectedEvents = append(expectedEvents, "evacuated "+evacuation.LRPStartAuction.InstanceGuid+" to "+evacuation.Winner)
+				}
+
+				//the observer also hears of every step of each instance's auction
+				evacuationEvents := []string{}
+				for _, event := range observer.Events() {
+					if strings.HasPrefix(event, "evacuated ") {
+						evacuationEvents = append(evacuationEvents, event)
+					}
+				}
+				Ω(evacuationEvents).Should(Equal(expectedEvents))
+
+				Ω(client.SimulatedInstances(repGuids[0])).Should(BeEmpty())
+				numInstances := 0
+				for _, repGuid := range repGuids[1:nexec] {
+					numInstances += len(client.SimulatedInstances(repGuid))
+				}
+				Ω(numInstances).Should(Equal(len(lrpStartAuctions)))
+			})
+
+			It("should leave the instances nobody else will take running where they are", func() {
+				result, err := evacuate(auctionrunner.New(client), repGuids[:1])
+				Ω(err).Should(Equal(auctiontypes.EvacuationIncomplete))
+
+				for _, evacuation := range result.Instances {
+					Ω(evacuation.Winner).Should(BeEmpty())
+					Ω(evacuation.Stopped).Should(BeFalse())
+					Ω(evacuation.Error).Should(Equal(auctiontypes.InsufficientResources.Error()))
+				}
+				Ω(client.SimulatedInstances(repGuids[0])).Should(HaveLen(len(lrpStartAuctions)))
+			})
+		})
+
+		Context("Admission control scenario", func() {
+			//reps that hold at most two reservations at once
+			var limitedClient auctiontypes.SimulationRepPoolClient
+			var limitedRepGuids []string
+
+			BeforeEach(func() {
+				limitedClient, limitedRepGuids = buildInProcessReps(4, auctionrep.Config{Limits: auctionrep.Limits{MaxReservations: 2}})
+			})
+
+			holdReservations := func(repGuid string, n int) []auctiontypes.StartAuctionInfo {
+				infos := []auctiontypes.StartAuctionInfo{}
+				for i := 0; i < n; i++ {
+					info := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(newLRPStartAuction("held", 1))
+					bids := limitedClient.RebidThenTentativelyReserve([]string{repGuid}, info)
+					Ω(bids).Should(HaveLen(1))
+					Ω(bids[0].Error).Should(BeEmpty())
+					infos = append(infos, info)
+				}
+				return infos
+			}
+
+			It("should refuse to bid as busy while at its limit", func() {
+				held := holdReservations(limitedRepGuids[0], 2)
 
-var _ = Describe("Auction", func() {
-	var initialDistributions map[int][]auctiontypes.SimulatedInstance
+				info := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(newLRPStartAuction("red", 1))
+				bids := limitedClient.BidForStartAuction(limitedRepGuids[:1], info)
+				Ω(bids).Should(HaveLen(1))
+				Ω(bids[0].Error).Should(Equal(auctiontypes.RepBusy.Error()))
 
-	newSimulatedInstance := func(processGuid string, index int, memoryMB int) auctiontypes.SimulatedInstance {
-		return auctiontypes.SimulatedInstance{
-			ProcessGuid:  processGuid,
-			InstanceGuid: util.NewGuid("INS"),
-			Index:        index,
-			MemoryMB:     memoryMB,
-			DiskMB:       1,
-		}
-	}
+				reservations := limitedClient.TentativelyReserveBatch(map[string][]auctiontypes.StartAuctionInfo{limitedRepGuids[0]: {info}})
+				Ω(reservations).Should(HaveLen(1))
+				Ω(reservations[0].Error).Should(Equal(auctiontypes.RepBusy.Error()))
 
-	generateUniqueSimulatedInstances := func(numInstances int, index int, memoryMB int) []auctiontypes.SimulatedInstance {
-		instances := []auctiontypes.SimulatedInstance{}
-		for i := 0; i < numInstances; i++ {
-			instances = append(instances, newSimulatedInstance(util.NewGrayscaleGuid("AAA"), index, memoryMB))
-		}
-		return instances
-	}
+				limitedClient.ReleaseReservation(limitedRepGuids[:1], held[0])
+				bids = limitedClient.BidForStartAuction(limitedRepGuids[:1], info)
+				Ω(bids[0].Error).Should(BeEmpty())
+			})
 
-	generateSimulatedInstancesForProcessGuid := func(processGuid string, numInstances int, index int, memoryMB int) []auctiontypes.SimulatedInstance {
-		instances := []auctiontypes.SimulatedInstance{}
-		for i := 0; i < numInstances; i++ {
-			instances = append(instances, newSimulatedInstance(processGuid, index, memoryMB))
-		}
-		return instances
-	}
+			It("should report busy rather than no room, without preempting anything", func() {
+				holdReservations(limitedRepGuids[0], 2)
 
-	newLRPStartAuction := func(processGuid string, memoryMB int) models.LRPStartAuction {
-		return models.LRPStartAuction{
-			ProcessGuid:  processGuid,
-			InstanceGuid: util.NewGuid("INS"),
-			MemoryMB:     memoryMB,
-			DiskMB:       1,
-			Index:        0,
-		}
-	}
+				rules := auctionrunner.DefaultStartAuctionRules
+				rules.MaxRounds = 3
+				rules.Preemption = true
+				result, err := auctionrunner.New(limitedClient).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction("red", 1),
+					RepGuids:        limitedRepGuids[:1],
+					Rules:           rules,
+					Priority:        1,
+				})
+				Ω(err).Should(Equal(auctiontypes.RepBusy))
+				Ω(result.Evicted).Should(BeEmpty())
+			})
 
-	generateUniqueLRPStartAuctions := func(numInstances int, memoryMB int) []models.LRPStartAuction {
-		instances := []models.LRPStartAuction{}
-		for i := 0; i < numInstances; i++ {
-			instances = append(instances, newLRPStartAuction(util.NewGrayscaleGuid("BBB"), memoryMB))
-		}
-		return instances
-	}
+			It("should still place a burst of concurrent auctions, asking busy reps again later", func() {
+				runner := auctionrunner.New(limitedClient)
+				lrpStartAuctions := generateUniqueLRPStartAuctions(20, 1)
 
-	randomSVGColor := func() string {
-		return []string{"purple", "red", "cyan", "teal", "gray", "blue", "pink", "green", "lime", "orange", "lightseagreen", "brown"}[util.R.Intn(12)]
-	}
+				errs := make(chan error, len(lrpStartAuctions))
+				for _, lrpStartAuction := range lrpStartAuctions {
+					go func(lrpStartAuction models.LRPStartAuction) {
+						_, err := runner.RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+							LRPStartAuction: lrpStartAuction,
+							RepGuids:        limitedRepGuids,
+							Rules:           auctionrunner.DefaultStartAuctionRules,
+						})
+						errs <- err
+					}(lrpStartAuction)
+				}
 
-	generateLRPStartAuctionsWithRandomSVGColors := func(numInstances int, memoryMB int) []models.LRPStartAuction {
-		instances := []models.LRPStartAuction{}
-		for i := 0; i < numInstances; i++ {
-			instances = append(instances, newLRPStartAuction(randomSVGColor(), memoryMB))
-		}
-		return instances
-	}
+				for _ = range lrpStartAuctions {
+					Ω(<-errs).ShouldNot(HaveOccurred())
+				}
 
-	generateLRPStartAuctionsForProcessGuid := func(numInstances int, processGuid string, memoryMB int) []models.LRPStartAuction {
-		instances := []models.LRPStartAuction{}
-		for i := 0; i < numInstances; i++ {
-			instances = append(instances, newLRPStartAuction(processGuid, memoryMB))
-		}
-		return instances
-	}
+				numInstances := 0
+				for _, repGuid := range limitedRepGuids {
+					numInstances += len(limitedClient.SimulatedInstances(repGuid))
+				}
+				Ω(numInstances).Should(Equal(len(lrpStartAuctions)))
+			})
+		})
 
-	BeforeEach(func() {
-		util.ResetGuids()
-		initialDistributions = map[int][]auctiontypes.SimulatedInstance{}
-	})
+		Context("Stop policy scenario", func() {
+			var oldestGuid string
 
-	JustBeforeEach(func() {
-		for index, simulatedInstances := range initialDistributions {
-			client.SetSimulatedInstances(repGuids[index], simulatedInstances)
-		}
-	})
+			BeforeEach(func() {
+				startedAt := func(instance auctiontypes.SimulatedInstance, age time.Duration) auctiontypes.SimulatedInstance {
+					instance.StartedAt = time.Now().Add(-age)
+					return instance
+				}
 
-	Describe("Experiments", func() {
-		Context("Cold start scenario", func() {
-			nexec := []int{25, 100}
-			n1apps := []int{1800, 7000}
-			n2apps := []int{200, 1000}
-			n4apps := []int{50, 200}
-			for i := range nexec {
-				i := i
-				Context("with variable memory requirements between apps", func() {
-					It("should distribute evenly", func() {
-						instances := []models.LRPStartAuction{}
+				//z1: the heavy-laden rep runs the oldest instance, and a fresh duplicate of it
+				oldest := startedAt(newSimulatedInstance("red", 0, 1), 3*time.Hour)
+				oldestGuid = oldest.InstanceGuid
+				initialDistributions[0] = generateUniqueSimulatedInstances(50, 0, 1)
+				initialDistributions[0] = append(initialDistributions[0], startedAt(newSimulatedInstance("red", 0, 1), time.Minute), oldest)
 
-						instances = append(instances, generateUniqueLRPStartAuctions(n1apps[i]/2, 1)...)
-						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n1apps[i]/2, 1)...)
-						instances = append(instances, generateUniqueLRPStartAuctions(n2apps[i]/2, 2)...)
-						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n2apps[i]/2, 2)...)
-						instances = append(instances, generateUniqueLRPStartAuctions(n4apps[i]/2, 4)...)
-						instances = append(instances, generateLRPStartAuctionsWithRandomSVGColors(n4apps[i]/2, 4)...)
+				//z2: the least loaded rep
+				initialDistributions[1] = generateUniqueSimulatedInstances(10, 0, 1)
+				initialDistributions[1] = append(initialDistributions[1], startedAt(newSimulatedInstance("red", 0, 1), time.Hour))
 
-						permutedInstances := make([]models.LRPStartAuction, len(instances))
-						for i, index := range util.R.Perm(len(instances)) {
-							permutedInstances[i] = instances[index]
+				//z1
+				initialDistributions[2] = generateUniqueSimulatedInstances(30, 0, 1)
+				initialDistributions[2] = append(initialDistributions[2], startedAt(newSimulatedInstance("red", 0, 1), 2*time.Hour))
+
+				//z2 already runs other instances of the process
+				initialDistributions[3] = generateSimulatedInstancesForProcessGuid("red", 2, 1, 1)
+			})
+
+			stopAuction := func(policy string) (auctiontypes.StopAuctionResult, error) {
+				return auctionrunner.New(client).RunLRPStopAuction(auctiontypes.StopAuctionRequest{
+					LRPStopAuction: models.LRPStopAuction{
+						ProcessGuid: "red",
+						Index:       0,
+					},
+					RepGuids: repGuids[:4],
+					Policy:   policy,
+				})
+			}
+
+			//the guids of the instances of index 0 left running, by rep
+			survivors := func() map[string][]string {
+				survivors := map[string][]string{}
+				for _, repGuid := range repGuids[:4] {
+					for _, instance := range client.SimulatedInstances(repGuid) {
+						if instance.ProcessGuid == "red" && instance.Index == 0 {
+							survivors[repGuid] = append(survivors[repGuid], instance.InstanceGuid)
 						}
-						report := auctionDistributor.HoldAuctionsFor(
-							"Cold start with variable memory requirements between apps",
-							nexec[i],
-							permutedInstances,
-							repGuids[:nexec[i]],
-							auctionrunner.DefaultStartAuctionRules,
-						)
+					}
+				}
+				return survivors
+			}
 
-						visualization.PrintReport(
-							client,
-							report.AuctionResults,
-							repGuids[:nexec[i]],
-							report.AuctionDuration,
-							auctionrunner.DefaultStartAuctionRules,
-						)
+			It("should keep the instance in the under-represented zone by default", func() {
+				Ω(client.Zone(repGuids[0])).Should(Equal(client.Zone(repGuids[2])))
+				Ω(client.Zone(repGuids[1])).Should(Equal(client.Zone(repGuids[3])))
 
-						svgReport.DrawReportCard(i, 0, report)
-						reports = append(reports, report)
-					})
+				result, err := stopAuction("")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[2]))
+				Ω(survivors()).Should(HaveLen(1))
+				Ω(survivors()[repGuids[2]]).Should(HaveLen(1))
+			})
+
+			It("should keep the instance on the least loaded rep, whatever its zone", func() {
+				result, err := stopAuction("least_loaded")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[1]))
+				Ω(survivors()).Should(HaveLen(1))
+				Ω(survivors()[repGuids[1]]).Should(HaveLen(1))
+			})
+
+			It("should keep the instance that has been running longest", func() {
+				result, err := stopAuction("oldest")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(result.Winner).Should(Equal(repGuids[0]))
+				Ω(survivors()).Should(Equal(map[string][]string{repGuids[0]: {oldestGuid}}))
+			})
+
+			It("should refuse policies it does not know, stopping nothing", func() {
+				_, err := stopAuction("youngest")
+				Ω(err).Should(Equal(auctionrunner.UnknownStopPolicyError{Policy: "youngest"}))
+				Ω(auctionrunner.StopPolicies()).Should(Equal([]string{"least_loaded", "oldest", "under_represented_zone"}))
+
+				numSurvivors := 0
+				for _, instanceGuids := range survivors() {
+					numSurvivors += len(instanceGuids)
+				}
+				Ω(numSurvivors).Should(Equal(4))
+			})
+
+			It("should bid what the rep would score once it ran a single instance of the index", func() {
+				info := auctionrep.StopIndexScoreInfo{
+					InstanceScoreInfo: auctionrep.StartInstanceScoreInfo{
+						RemainingResources:         auctiontypes.Resources{auctiontypes.MemoryMB: 70, auctiontypes.DiskMB: 40, auctiontypes.Containers: 90},
+						TotalResources:             auctiontypes.Resources{auctiontypes.MemoryMB: 100, auctiontypes.DiskMB: 100, auctiontypes.Containers: 100},
+						NumInstancesForProcessGuid: 3,
+					},
+					InstanceGuidsForProcessIndex: []string{"ins-a", "ins-b"},
+				}
+				Ω(auctionrep.DefaultStopScorer.ScoreStop(info)).Should(BeNumerically("~", (0.3+0.6+0.1)/3.0+2, 1e-9))
+			})
+
+			It("should bid the lower the older the rep's oldest instance", func() {
+				scoreStop := func(ages ...time.Duration) float64 {
+					info := auctionrep.StopIndexScoreInfo{InstanceStartTimes: map[string]time.Time{}}
+					for i, age := range ages {
+						info.InstanceStartTimes[strconv.Itoa(i)] = time.Now().Add(-age)
+					}
+					return auctionrep.OldestInstanceStopScorer{}.ScoreStop(info)
+				}
+
+				Ω(scoreStop()).Should(BeZero())
+				Ω(scoreStop(time.Hour, time.Minute)).Should(BeNumerically("<", scoreStop(time.Minute)))
+				Ω(scoreStop(time.Hour, time.Minute)).Should(BeNumerically("~", -3600, 1))
+			})
+		})
+
+		Context("Duplicate submission scenario", func() {
+			nexec := 10
+
+			placements := func(instanceGuid string) int {
+				n := 0
+				for _, repGuid := range repGuids[:nexec] {
+					for _, instance := range client.SimulatedInstances(repGuid) {
+						if instance.InstanceGuid == instanceGuid {
+							n++
+						}
+					}
+				}
+				return n
+			}
+
+			It("should auction an instance submitted many times at once only once", func() {
+				algorithm := auctionrunner.DefaultStartAuctionRules.Algorithm
+				before := scrapeMetrics()
+
+				runner := auctionrunner.New(client)
+				lrpStartAuction := newLRPStartAuction("red", 1)
+
+				numSubmissions := 10
+				gate := make(chan bool)
+				errs := make(chan error, numSubmissions)
+				for i := 0; i < numSubmissions; i++ {
+					go func() {
+						<-gate
+						_, err := runner.RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+							LRPStartAuction: lrpStartAuction,
+							RepGuids:        repGuids[:nexec],
+							Rules:           auctionrunner.DefaultStartAuctionRules,
+						})
+						errs <- err
+					}()
+				}
+				close(gate)
+
+				numPlaced := 0
+				for i := 0; i < numSubmissions; i++ {
+					err := <-errs
+					if err == nil {
+						numPlaced++
+					} else {
+						Ω(err).Should(Equal(auctiontypes.AuctionInProgress))
+					}
+				}
+
+				Ω(numPlaced).Should(Equal(1))
+				Ω(placements(lrpStartAuction.InstanceGuid)).Should(Equal(1))
+
+				//the turned-away duplicates are neither started nor failed auctions
+				after := scrapeMetrics()
+				Ω(after[`auction_start_auctions_started_total{algorithm="`+algorithm+`"}`] - before[`auction_start_auctions_started_total{algorithm="`+algorithm+`"}`]).Should(Equal(1.0))
+				Ω(after[`auction_start_auctions_duplicated_total{algorithm="`+algorithm+`"}`] - before[`auction_start_auctions_duplicated_total{algorithm="`+algorithm+`"}`]).Should(Equal(float64(numSubmissions - 1)))
+				for series := range after {
+					if strings.HasPrefix(series, "auction_start_auctions_failed_total{") {
+						Ω(after[series]).Should(Equal(before[series]), series)
+					}
+				}
+			})
+
+			It("should auction an instance repeated within a batch only once", func() {
+				lrpStartAuction := newLRPStartAuction("red", 1)
+				results, err := auctionrunner.New(client).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+					LRPStartAuctions: []models.LRPStartAuction{lrpStartAuction, lrpStartAuction},
+					RepGuids:         repGuids[:nexec],
+					Rules:            auctionrunner.DefaultStartAuctionRules,
+				})
+				Ω(err).Should(Equal(auctiontypes.AuctionInProgress))
+				Ω(results).Should(HaveLen(2))
+				Ω(results[0].Winner).ShouldNot(BeEmpty())
+				Ω(results[1].Winner).Should(BeEmpty())
+				Ω(placements(lrpStartAuction.InstanceGuid)).Should(Equal(1))
+			})
+
+			It("should report how the auctioned instances of a batch fared, whatever their duplicates", func() {
+				duplicate := newLRPStartAuction("red", 1)
+				unplaceable := newLRPStartAuction("blue", 1<<20)
+				before := scrapeMetrics()
+
+				observer := newRecordingObserver()
+				results, err := auctionrunner.New(client, observer).RunLRPStartAuctionBatch(auctiontypes.StartAuctionBatchRequest{
+					LRPStartAuctions: []models.LRPStartAuction{duplicate, duplicate, unplaceable},
+					RepGuids:         repGuids[:nexec],
+					Rules:            auctionrunner.DefaultStartAuctionRules,
+				})
+				Ω(err).Should(Equal(auctiontypes.InsufficientResources))
+				Ω(results[0].Winner).ShouldNot(BeEmpty())
+				Ω(results[1].Winner).Should(BeEmpty())
+				Ω(results[2].Winner).Should(BeEmpty())
+				Ω(observer.Events()).Should(ConsistOf("auction-finished "+results[0].Winner, "auction-finished "))
+
+				after := scrapeMetrics()
+				delta := func(series string) float64 {
+					return after[series] - before[series]
+				}
+				Ω(delta(`auction_start_auctions_started_total{algorithm="batch"}`)).Should(Equal(2.0))
+				Ω(delta(`auction_start_auctions_succeeded_total{algorithm="batch"}`)).Should(Equal(1.0))
+				Ω(delta(`auction_start_auctions_failed_total{algorithm="batch",reason="insufficient-resources"}`)).Should(Equal(1.0))
+				Ω(delta(`auction_start_auctions_duplicated_total{algorithm="batch"}`)).Should(Equal(1.0))
+			})
+
+			It("should have reps refuse to reserve an instance they already hold", func() {
+				startAuctionInfo := auctiontypes.NewStartAuctionInfoFromLRPStartAuction(newLRPStartAuction("red", 1))
+
+				bids := client.RebidThenTentativelyReserve(repGuids[:1], startAuctionInfo)
+				Ω(bids[0].Error).Should(BeEmpty())
+
+				bids = client.RebidThenTentativelyReserve(repGuids[:1], startAuctionInfo)
+				Ω(bids[0].Error).Should(Equal(auctiontypes.DuplicateInstance.Error()))
+
+				Ω(placements(startAuctionInfo.InstanceGuid)).Should(Equal(1))
+			})
+		})
+
+		Context("Metrics scenario", func() {
+			BeforeEach(func() {
+				initialDistributions[0] = generateUniqueSimulatedInstances(repResources[auctiontypes.Containers]-1, 0, 0)
+			})
+
+			startAuction := func(processGuid string) error {
+				_, err := auctionrunner.New(client).RunLRPStartAuction(auctiontypes.StartAuctionRequest{
+					LRPStartAuction: newLRPStartAuction(processGuid, 1),
+					RepGuids:        repGuids[:1],
+					Rules:           auctionrunner.DefaultStartAuctionRules,
 				})
+				return err
 			}
+
+			It("should count the auctions that placed their instance and the ones that did not, and why", func() {
+				algorithm := auctionrunner.DefaultStartAuctionRules.Algorithm
+				before := scrapeMetrics()
+
+				Ω(startAuction("red")).ShouldNot(HaveOccurred())
+				Ω(startAuction("blue")).Should(Equal(auctiontypes.InsufficientResources))
+
+				after := scrapeMetrics()
+				delta := func(series string) float64 {
+					return after[series] - before[series]
+				}
+
+				Ω(delta(`auction_start_auctions_started_total{algorithm="` + algorithm + `"}`)).Should(Equal(2.0))
+				Ω(delta(`auction_start_auctions_succeeded_total{algorithm="` + algorithm + `"}`)).Should(Equal(1.0))
+				Ω(delta(`auction_start_auctions_failed_total{algorithm="` + algorithm + `",reason="insufficient-resources"}`)).Should(Equal(1.0))
+				Ω(delta(`auction_start_auction_rounds_count{algorithm="` + algorithm + `"}`)).Should(Equal(2.0))
+				Ω(delta(`auction_start_auction_communications_sum{algorithm="` + algorithm + `"}`)).Should(BeNumerically(">", 0))
+
+				if communicationMode == InProcess {
+					Ω(after[`auction_rep_reservations_held{rep="`+repGuids[0]+`"}`]).Should(BeZero())
+				}
+			})
 		})
 
 		Context("Imbalanced scenario (e.g. a deploy)", func() {
@@ -307,6 +2262,40 @@ var _ = Describe("Auction", func() {
 				})
 			})
 
+			Context("when the executor with more available resources is in a zone that already runs another instance of the app", func() {
+				BeforeEach(func() {
+					initialDistributions[0] = generateUniqueSimulatedInstances(50, 0, 1)
+					initialDistributions[0] = append(initialDistributions[0], generateSimulatedInstancesForProcessGuid(processGuid, 1, 0, 1)...)
+
+					initialDistributions[1] = generateUniqueSimulatedInstances(30, 0, 1)
+					initialDistributions[1] = append(initialDistributions[1], generateSimulatedInstancesForProcessGuid(processGuid, 1, 0, 1)...)
+
+					initialDistributions[3] = generateSimulatedInstancesForProcessGuid(processGuid, 1, 1, 1)
+				})
+
+				It("should favor leaving the instance in the other zone", func() {
+					Ω(client.Zone(repGuids[0])).ShouldNot(Equal(client.Zone(repGuids[1])))
+					Ω(client.Zone(repGuids[1])).Should(Equal(client.Zone(repGuids[3])))
+
+					stopAuctions := []models.LRPStopAuction{
+						{
+							ProcessGuid: processGuid,
+							Index:       0,
+						},
+					}
+
+					results := auctionDistributor.HoldStopAuctions(stopAuctions, repGuids)
+					Ω(results).Should(HaveLen(1))
+					Ω(results[0].Winner).Should(Equal("REP-1"))
+
+					instancesOn0 := client.SimulatedInstances(repGuids[0])
+					instancesOn1 := client.SimulatedInstances(repGuids[1])
+
+					Ω(instancesOn0).Should(HaveLen(51))
+					Ω(instancesOn1).Should(HaveLen(30))
+				})
+			})
+
 			Context("when there are very many duplicate instances out there", func() {
 				BeforeEach(func() {
 					initialDistributions[0] = generateSimulatedInstancesForProcessGuid(processGuid, 50, 0, 1)
@@ -343,3 +2332,264 @@ var _ = Describe("Auction", func() {
 		})
 	})
 })
+
+// the suite registers an algorithm of its own: it hands every auction to pick_best, counting them
+const delegatingAlgorithm = "delegate_to_pick_best"
+
+var delegatedAuctions int32
+
+func init() {
+	auctionrunner.RegisterStartAuctionAlgorithm(delegatingAlgorithm, delegateToPickBest)
+	auctionrunner.RegisterDryRunStartAuctionAlgorithm(delegatingAlgorithm, dryRunDelegateToPickBest)
+}
+
+func delegateToPickBest(ctx context.Context, client auctiontypes.RepPoolClient, auctionRequest auctiontypes.StartAuctionRequest) (string, int, int) {
+	atomic.AddInt32(&delegatedAuctions, 1)
+
+	auctionRequest.Rules.Algorithm = "pick_best"
+	result, _ := auctionrunner.New(client).RunLRPStartAuctionWithContext(ctx, auctionRequest)
+	return result.Winner, result.NumRounds, result.NumCommunications
+}
+
+func dryRunDelegateToPickBest(ctx context.Context, bidder auctionrunner.StartAuctionBidder, auctionRequest auctiontypes.StartAuctionRequest) (string, auctiontypes.StartAuctionBids, int, int) {
+	bids := bidder.BidForStartAuction(auctionRequest.RepGuids, auctiontypes.NewStartAuctionInfoFromStartAuctionRequest(auctionRequest))
+	if bids.AllFailed() {
+		return "", bids.Ranked(), 1, len(auctionRequest.RepGuids)
+	}
+
+	ranked := bids.Ranked()
+	return ranked[0].Rep, ranked, 1, len(auctionRequest.RepGuids)
+}
+
+// recordingObserver notes the steps of the auctions it observes
+type recordingObserver struct {
+	lock   *sync.Mutex
+	events []string
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{
+		lock: &sync.Mutex{},
+	}
+}
+
+func (o *recordingObserver) record(event string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *recordingObserver) Events() []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.events
+}
+
+func (o *recordingObserver) RoundStarted(startAuction models.LRPStartAuction, round int) {
+	o.record(fmt.Sprintf("round-started %d", round))
+}
+
+func (o *recordingObserver) BidsReceived(startAuction models.LRPStartAuction, repGuids []string, bids auctiontypes.StartAuctionBids) {
+	o.record(fmt.Sprintf("bids-received %d", len(bids)))
+}
+
+func (o *recordingObserver) ReservationAttempted(startAuction models.LRPStartAuction, repGuids []string, bids auctiontypes.StartAuctionBids) {
+	o.record(fmt.Sprintf("reservation-attempted %v", bids.FilterErrors().Reps()))
+}
+
+func (o *recordingObserver) ReservationReleased(startAuction models.LRPStartAuction, repGuids []string) {
+	o.record(fmt.Sprintf("reservation-released %v", repGuids))
+}
+
+func (o *recordingObserver) Ran(startAuction models.LRPStartAuction, repGuid string, err error) {
+	if err != nil {
+		o.record("ran " + repGuid + " failed")
+	} else {
+		o.record("ran " + repGuid)
+	}
+}
+
+func (o *recordingObserver) RoundAbandoned(startAuction models.LRPStartAuction, round int, reason string) {
+	o.record(fmt.Sprintf("round-abandoned %d", round))
+}
+
+func (o *recordingObserver) AuctionFinished(result auctiontypes.StartAuctionResult, err error) {
+	o.record("auction-finished " + result.Winner)
+}
+
+func (o *recordingObserver) InstanceEvacuated(repGuid string, evacuation auctiontypes.InstanceEvacuation) {
+	o.record("evacuated " + evacuation.LRPStartAuction.InstanceGuid + " to " + evacuation.Winner)
+}
+
+// bidRecordingClient notes, round by round, the bids asked for and the reps then asked to reserve
+type bidRecordingClient struct {
+	auctiontypes.SimulationRepPoolClient
+	lock   *sync.Mutex
+	rounds []biddingRound
+}
+
+type biddingRound struct {
+	Bids     auctiontypes.StartAuctionBids
+	Reserved []string
+}
+
+func newBidRecordingClient(client auctiontypes.SimulationRepPoolClient) *bidRecordingClient {
+	return &bidRecordingClient{
+		SimulationRepPoolClient: client,
+		lock:                    &sync.Mutex{},
+	}
+}
+
+func (c *bidRecordingClient) Rounds() []biddingRound {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.rounds
+}
+
+func (c *bidRecordingClient) BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.SimulationRepPoolClient.BidForStartAuction(repGuids, startAuctionInfo)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rounds = append(c.rounds, biddingRound{Bids: bids})
+	return bids
+}
+
+func (c *bidRecordingClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	c.lock.Lock()
+	c.rounds[len(c.rounds)-1].Reserved = append(c.rounds[len(c.rounds)-1].Reserved, repGuids...)
+	c.lock.Unlock()
+
+	return c.SimulationRepPoolClient.RebidThenTentativelyReserve(repGuids, startAuctionInfo)
+}
+
+// interruptingClient calls interrupt every time a rep has just reserved, as if the auction ran out of time or was cancelled right then
+type interruptingClient struct {
+	auctiontypes.SimulationRepPoolClient
+	interrupt     func()
+	interruptions int32
+}
+
+func newInterruptingClient(client auctiontypes.SimulationRepPoolClient, interrupt func()) *interruptingClient {
+	return &interruptingClient{
+		SimulationRepPoolClient: client,
+		interrupt:               interrupt,
+	}
+}
+
+func (c *interruptingClient) Interruptions() int {
+	return int(atomic.LoadInt32(&c.interruptions))
+}
+
+func (c *interruptingClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := c.SimulationRepPoolClient.RebidThenTentativelyReserve(repGuids, startAuctionInfo)
+	atomic.AddInt32(&c.interruptions, 1)
+	c.interrupt()
+	return bids
+}
+
+func (c *interruptingClient) TentativelyReserveBatch(startAuctionInfosByRepGuid map[string][]auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionReservations {
+	reservations := c.SimulationRepPoolClient.TentativelyReserveBatch(startAuctionInfosByRepGuid)
+	atomic.AddInt32(&c.interruptions, 1)
+	c.interrupt()
+	return reservations
+}
+
+// refusingClient has every rep refuse to reserve, whatever it bid
+type refusingClient struct {
+	auctiontypes.SimulationRepPoolClient
+}
+
+func newRefusingClient(client auctiontypes.SimulationRepPoolClient) *refusingClient {
+	return &refusingClient{SimulationRepPoolClient: client}
+}
+
+func (c *refusingClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	bids := auctiontypes.StartAuctionBids{}
+	for _, repGuid := range repGuids {
+		bids = append(bids, auctiontypes.StartAuctionBid{Rep: repGuid, Error: "refused"})
+	}
+	return bids
+}
+
+// tracingClient notes which requests were sent under which trace ID
+type tracingClient struct {
+	auctiontypes.SimulationRepPoolClient
+	traceID  string
+	lock     *sync.Mutex
+	requests map[string][]string
+}
+
+func newTracingClient(client auctiontypes.SimulationRepPoolClient) *tracingClient {
+	return &tracingClient{
+		SimulationRepPoolClient: client,
+		lock:                    &sync.Mutex{},
+		requests:                map[string][]string{},
+	}
+}
+
+func (c *tracingClient) WithTraceID(traceID string) auctiontypes.RepPoolClient {
+	traced := *c
+	traced.traceID = traceID
+	return &traced
+}
+
+func (c *tracingClient) Requests(traceID string) []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.requests[traceID]
+}
+
+func (c *tracingClient) record(request string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.requests[c.traceID] = append(c.requests[c.traceID], request)
+}
+
+func (c *tracingClient) BidForStartAuction(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	c.record("bid")
+	return c.SimulationRepPoolClient.BidForStartAuction(repGuids, startAuctionInfo)
+}
+
+func (c *tracingClient) RebidThenTentativelyReserve(repGuids []string, startAuctionInfo auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionBids {
+	c.record("reserve")
+	return c.SimulationRepPoolClient.RebidThenTentativelyReserve(repGuids, startAuctionInfo)
+}
+
+func (c *tracingClient) BidForBatchStartAuction(repGuids []string, startAuctionInfos []auctiontypes.StartAuctionInfo) auctiontypes.BatchStartAuctionBids {
+	c.record("batch-bid")
+	return c.SimulationRepPoolClient.BidForBatchStartAuction(repGuids, startAuctionInfos)
+}
+
+func (c *tracingClient) TentativelyReserveBatch(startAuctionInfosByRepGuid map[string][]auctiontypes.StartAuctionInfo) auctiontypes.StartAuctionReservations {
+	c.record("batch-reserve")
+	return c.SimulationRepPoolClient.TentativelyReserveBatch(startAuctionInfosByRepGuid)
+}
+
+func (c *tracingClient) Run(repGuid string, startAuction models.LRPStartAuction) error {
+	c.record("run " + repGuid)
+	return c.SimulationRepPoolClient.Run(repGuid, startAuction)
+}
+
+func (c *tracingClient) Stop(repGuid string, stopInstance models.StopLRPInstance) error {
+	c.record("stop " + repGuid)
+	return c.SimulationRepPoolClient.Stop(repGuid, stopInstance)
+}
+
+// scrapeMetrics renders the default registry and reads back the value of every series
+func scrapeMetrics() map[string]float64 {
+	out := &bytes.Buffer{}
+	metrics.Default.WriteTo(out)
+
+	values := map[string]float64{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndex(line, " ")
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		Ω(err).ShouldNot(HaveOccurred())
+		values[line[:i]] = value
+	}
+	return values
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/cloudfoundry-incubator/auction/auctiontypes"
 	"github.com/cloudfoundry-incubator/runtime-schema/models"
@@ -12,18 +13,34 @@ import (
 type SimulationRepDelegate struct {
 	lock           *sync.Mutex
 	instances      map[string]auctiontypes.SimulatedInstance
+	reservations   map[string]auctiontypes.Reservation
 	totalResources auctiontypes.Resources
+	remaining      auctiontypes.Resources
+	zone           string
+	attributes     []string
+	failsToRun     bool
 }
 
-func New(totalResources auctiontypes.Resources) auctiontypes.SimulationAuctionRepDelegate {
+func New(totalResources auctiontypes.Resources, zone string, attributes []string) auctiontypes.SimulationAuctionRepDelegate {
 	return &SimulationRepDelegate{
 		totalResources: totalResources,
+		zone:           zone,
+		attributes:     attributes,
 
-		lock:      &sync.Mutex{},
-		instances: map[string]auctiontypes.SimulatedInstance{},
+		lock:         &sync.Mutex{},
+		instances:    map[string]auctiontypes.SimulatedInstance{},
+		reservations: map[string]auctiontypes.Reservation{},
 	}
 }
 
+func (rep *SimulationRepDelegate) Zone() (string, error) {
+	return rep.zone, nil
+}
+
+func (rep *SimulationRepDelegate) Attributes() ([]string, error) {
+	return rep.attributes, nil
+}
+
 func (rep *SimulationRepDelegate) RemainingResources() (auctiontypes.Resources, error) {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
@@ -50,6 +67,47 @@ func (rep *SimulationRepDelegate) NumInstancesForProcessGuid(processGuid string)
 	return n, nil
 }
 
+func (rep *SimulationRepDelegate) ProcessGuids() ([]string, error) {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	processGuids := []string{}
+	seen := map[string]bool{}
+
+	for _, instance := range rep.instances {
+		if !seen[instance.ProcessGuid] {
+			seen[instance.ProcessGuid] = true
+			processGuids = append(processGuids, instance.ProcessGuid)
+		}
+	}
+
+	return processGuids, nil
+}
+
+func (rep *SimulationRepDelegate) EvictableInstances(priority int) ([]auctiontypes.EvictableInstance, error) {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	evictable := []auctiontypes.EvictableInstance{}
+
+	//a reserved instance is another auction's, about to be run: only running instances are evicted
+	for _, instance := range rep.instances {
+		if instance.Priority < priority && !instance.Reserved {
+			evictable = append(evictable, auctiontypes.EvictableInstance{
+				ProcessGuid:  instance.ProcessGuid,
+				InstanceGuid: instance.InstanceGuid,
+				Index:        instance.Index,
+				Priority:     instance.Priority,
+				MemoryMB:     instance.MemoryMB,
+				DiskMB:       instance.DiskMB,
+				Resources:    instance.Resources,
+			})
+		}
+	}
+
+	return evictable, nil
+}
+
 func (rep *SimulationRepDelegate) InstanceGuidsForProcessGuidAndIndex(processGuid string, index int) ([]string, error) {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
@@ -65,41 +123,77 @@ func (rep *SimulationRepDelegate) InstanceGuidsForProcessGuidAndIndex(processGui
 	return instanceGuids, nil
 }
 
-func (rep *SimulationRepDelegate) Reserve(startAuctionInfo auctiontypes.StartAuctionInfo) error {
+// instances whose start time is unknown (e.g. set up by the simulation without one) are left out
+func (rep *SimulationRepDelegate) InstanceStartTimes(instanceGuids []string) (map[string]time.Time, error) {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
 
-	remaining := rep.remainingResources()
+	startTimes := map[string]time.Time{}
+	for _, instanceGuid := range instanceGuids {
+		instance, ok := rep.instances[instanceGuid]
+		if ok && !instance.StartedAt.IsZero() {
+			startTimes[instanceGuid] = instance.StartedAt
+		}
+	}
 
-	hasEnoughMemory := remaining.MemoryMB >= startAuctionInfo.MemoryMB
-	hasEnoughDisk := remaining.DiskMB >= startAuctionInfo.DiskMB
-	hasEnoughContainers := remaining.Containers > 0
+	return startTimes, nil
+}
+
+func (rep *SimulationRepDelegate) Reservations() ([]auctiontypes.Reservation, error) {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
 
-	if !(hasEnoughMemory && hasEnoughDisk && hasEnoughContainers) {
-		return auctiontypes.InsufficientResources
+	reservations := []auctiontypes.Reservation{}
+	for _, reservation := range rep.reservations {
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, nil
+}
+
+func (rep *SimulationRepDelegate) Reserve(startAuctionInfo auctiontypes.StartAuctionInfo) error {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	_, ok := rep.instances[startAuctionInfo.InstanceGuid]
+	if ok {
+		return auctiontypes.DuplicateInstance
 	}
 
+	//the rep has already made sure it has room, counting any overcommit: an overcommitted rep runs into what it really has
+	rep.remaining = nil
 	rep.instances[startAuctionInfo.InstanceGuid] = auctiontypes.SimulatedInstance{
 		ProcessGuid:  startAuctionInfo.ProcessGuid,
 		InstanceGuid: startAuctionInfo.InstanceGuid,
 		MemoryMB:     startAuctionInfo.MemoryMB,
 		DiskMB:       startAuctionInfo.DiskMB,
+		Resources:    startAuctionInfo.Resources,
 		Index:        startAuctionInfo.Index,
+		Priority:     startAuctionInfo.Priority,
+		Reserved:     true,
+	}
+
+	rep.reservations[startAuctionInfo.InstanceGuid] = auctiontypes.Reservation{
+		StartAuctionInfo: startAuctionInfo,
+		ReservedAt:       time.Now(),
 	}
 
 	return nil
 }
 
+// only an outstanding reservation is released: a late reaper or a second release must not stop an instance that is running
 func (rep *SimulationRepDelegate) ReleaseReservation(startAuctionInfo auctiontypes.StartAuctionInfo) error {
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
 
-	_, ok := rep.instances[startAuctionInfo.InstanceGuid]
+	_, ok := rep.reservations[startAuctionInfo.InstanceGuid]
 	if !ok {
 		return errors.New(fmt.Sprintf("no reservation for instance %s", startAuctionInfo.InstanceGuid))
 	}
 
+	rep.remaining = nil
 	delete(rep.instances, startAuctionInfo.InstanceGuid)
+	delete(rep.reservations, startAuctionInfo.InstanceGuid)
 
 	return nil
 }
@@ -108,11 +202,20 @@ func (rep *SimulationRepDelegate) Run(startAuction models.LRPStartAuction) error
 	rep.lock.Lock()
 	defer rep.lock.Unlock()
 
-	_, ok := rep.instances[startAuction.InstanceGuid]
+	instance, ok := rep.instances[startAuction.InstanceGuid]
 	if !ok {
 		return errors.New(fmt.Sprintf("no reservation for instance %s", startAuction.InstanceGuid))
 	}
 
+	if rep.failsToRun {
+		return errors.New(fmt.Sprintf("failed to run instance %s", startAuction.InstanceGuid))
+	}
+
+	instance.Reserved = false
+	instance.StartedAt = time.Now()
+	rep.instances[startAuction.InstanceGuid] = instance
+	delete(rep.reservations, startAuction.InstanceGuid)
+
 	//start the app asynchronously!
 
 	return nil
@@ -127,7 +230,9 @@ func (rep *SimulationRepDelegate) Stop(stopInstance models.StopLRPInstance) erro
 		return errors.New(fmt.Sprintf("no reservation for instance %s", stopInstance.InstanceGuid))
 	}
 
+	rep.remaining = nil
 	delete(rep.instances, stopInstance.InstanceGuid)
+	delete(rep.reservations, stopInstance.InstanceGuid)
 
 	return nil
 }
@@ -143,7 +248,16 @@ func (rep *SimulationRepDelegate) SetSimulatedInstances(instances []auctiontypes
 		instancesMap[instance.InstanceGuid] = instance
 	}
 
+	rep.remaining = nil
 	rep.instances = instancesMap
+	rep.reservations = map[string]auctiontypes.Reservation{}
+}
+
+func (rep *SimulationRepDelegate) SetFailsToRun(failsToRun bool) {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	rep.failsToRun = failsToRun
 }
 
 func (rep *SimulationRepDelegate) SimulatedInstances() []auctiontypes.SimulatedInstance {
@@ -159,12 +273,14 @@ func (rep *SimulationRepDelegate) SimulatedInstances() []auctiontypes.SimulatedI
 
 //internal
 
+// computed again only once the instances have changed: bids ask for it far more often than instances come and go
 func (rep *SimulationRepDelegate) remainingResources() auctiontypes.Resources {
-	resources := rep.totalResources
-	for _, instance := range rep.instances {
-		resources.MemoryMB -= instance.MemoryMB
-		resources.DiskMB -= instance.DiskMB
-		resources.Containers -= 1
+	if rep.remaining == nil {
+		instances := make([]auctiontypes.SimulatedInstance, 0, len(rep.instances))
+		for _, instance := range rep.instances {
+			instances = append(instances, instance)
+		}
+		rep.remaining = rep.totalResources.Subtract(auctiontypes.TotalDemand(instances))
 	}
-	return resources
+	return rep.remaining
 }
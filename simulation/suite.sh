@@ -5,6 +5,7 @@ ginkgo -- -algorithm=all_reserve -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -algorithm=reserve_n_best -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -algorithm=pick_best -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -algorithm=random -maxBiddingPool=20 -maxConcurrent=20
+ginkgo -- -algorithm=pick_two -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -algorithm=pick_among_best -maxBiddingPool=20 -maxConcurrent=20
 
 ginkgo -- -algorithm=all_rebid -maxBiddingPool=100 -maxConcurrent=20
@@ -12,6 +13,7 @@ ginkgo -- -algorithm=all_reserve -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -algorithm=reserve_n_best -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -algorithm=pick_best -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -algorithm=random -maxBiddingPool=100 -maxConcurrent=20
+ginkgo -- -algorithm=pick_two -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -algorithm=pick_among_best -maxBiddingPool=100 -maxConcurrent=20
 
 ginkgo -- -algorithm=all_rebid -maxBiddingPool=20 -maxConcurrent=100
@@ -19,6 +21,7 @@ ginkgo -- -algorithm=all_reserve -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -algorithm=reserve_n_best -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -algorithm=pick_best -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -algorithm=random -maxBiddingPool=20 -maxConcurrent=100
+ginkgo -- -algorithm=pick_two -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -algorithm=pick_among_best -maxBiddingPool=20 -maxConcurrent=100
 
 ginkgo -- -algorithm=all_rebid -maxBiddingPool=20 -maxConcurrent=1000
@@ -26,6 +29,7 @@ ginkgo -- -algorithm=all_reserve -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -algorithm=reserve_n_best -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -algorithm=pick_best -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -algorithm=random -maxBiddingPool=20 -maxConcurrent=1000
+ginkgo -- -algorithm=pick_two -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -algorithm=pick_among_best -maxBiddingPool=20 -maxConcurrent=1000
 
 ginkgo -- -algorithm=all_rebid -maxBiddingPool=100 -maxConcurrent=100
@@ -33,6 +37,7 @@ ginkgo -- -algorithm=all_reserve -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -algorithm=reserve_n_best -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -algorithm=pick_best -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -algorithm=random -maxBiddingPool=100 -maxConcurrent=100
+ginkgo -- -algorithm=pick_two -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -algorithm=pick_among_best -maxBiddingPool=100 -maxConcurrent=100
 
 ginkgo -- -algorithm=all_rebid -maxBiddingPool=100 -maxConcurrent=1000
@@ -40,40 +45,47 @@ ginkgo -- -algorithm=all_reserve -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -algorithm=reserve_n_best -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -algorithm=pick_best -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -algorithm=random -maxBiddingPool=100 -maxConcurrent=1000
+ginkgo -- -algorithm=pick_two -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -algorithm=pick_among_best -maxBiddingPool=100 -maxConcurrent=1000
 
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_reserve -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=20 -maxConcurrent=20
+ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=20 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=20 -maxConcurrent=20
 
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_reserve -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=100 -maxConcurrent=20
+ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=100 -maxConcurrent=20
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=100 -maxConcurrent=20
 
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_reserve -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=20 -maxConcurrent=100
+ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=20 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=20 -maxConcurrent=100
 
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_reserve -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=20 -maxConcurrent=1000
+ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=20 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=20 -maxConcurrent=1000
 
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_reserve -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=100 -maxConcurrent=100
+ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=100 -maxConcurrent=100
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=100 -maxConcurrent=100
 
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_rebid -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=all_reserve -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=reserve_n_best -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=random -maxBiddingPool=100 -maxConcurrent=1000
+ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_two -maxBiddingPool=100 -maxConcurrent=1000
 ginkgo -- -communicationMode=nats -auctioneerMode=remote -algorithm=pick_among_best -maxBiddingPool=100 -maxConcurrent=1000
@@ -0,0 +1,34 @@
+package visualization
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/auction/auctiontypes"
+)
+
+func PrintHistory(result auctiontypes.StartAuctionResult) {
+	fmt.Printf("\n*** HISTORY OF %s (%d rounds) ***\n", result.LRPStartAuction.InstanceGuid, len(result.History))
+
+	for _, round := range result.History {
+		fmt.Printf("\n%sRound %d%s\n", boldStyle, round.Round, defaultStyle)
+		fmt.Printf("  Sampled: %d reps, %d bids failed\n", len(round.Sampled), len(round.Bids)-len(round.Bids.FilterErrors()))
+
+		successful := round.Bids.FilterErrors()
+		if len(successful) > 0 {
+			best := successful.Sort()[0]
+			fmt.Printf("  Best Bid: %s (%.4f)\n", best.Rep, best.Bid)
+		}
+		if len(round.Reserved) > 0 {
+			fmt.Printf("  Reserved: %v\n", round.Reserved)
+		}
+		if len(round.Released) > 0 {
+			fmt.Printf("  Released: %v\n", round.Released)
+		}
+		if round.Ran != "" {
+			fmt.Printf("  %sRan: %s%s\n", greenColor, round.Ran, defaultStyle)
+		}
+		if round.Abandoned != "" {
+			fmt.Printf("  %sAbandoned: %s%s\n", redColor, round.Abandoned, defaultStyle)
+		}
+	}
+}
@@ -107,10 +107,50 @@ func PrintReport(
 	excessMaxColocationFactorStats.UpdateArray(excessMaxColocationFactorData)
 	fmt.Printf("  Min: %.4f | Max: %.4f | Mean: %.4f | Variance: %.4f\n", excessMaxColocationFactorStats.Min(), excessMaxColocationFactorStats.Max(), excessMaxColocationFactorStats.Mean(), excessMaxColocationFactorStats.PopulationVariance())
 
+	excessZoneFactorData := []float64{}
+
+	numInstancesForProcessByZone := make(map[string]map[string]int)
+	zones := make(map[string]bool)
+	for _, repGuid := range representatives {
+		zone := client.Zone(repGuid)
+		if zone == "" {
+			continue
+		}
+		zones[zone] = true
+
+		for _, instance := range client.SimulatedInstances(repGuid) {
+			if numInstancesForProcessByZone[instance.ProcessGuid] == nil {
+				numInstancesForProcessByZone[instance.ProcessGuid] = make(map[string]int)
+			}
+			numInstancesForProcessByZone[instance.ProcessGuid][zone] += 1
+		}
+	}
+	for processGuid, numInstancesByZone := range numInstancesForProcessByZone {
+		maxNumInstancesInZone := 0
+		for _, numInstances := range numInstancesByZone {
+			if numInstances > maxNumInstancesInZone {
+				maxNumInstancesInZone = numInstances
+			}
+		}
+		expectedMaxNumInstancesInZone := math.Ceil(float64(totalNumInstancesForProcess[processGuid]) / float64(len(zones)))
+		excessZoneFactorData = append(
+			excessZoneFactorData,
+			float64(maxNumInstancesInZone)/expectedMaxNumInstancesInZone,
+		)
+	}
+
+	if len(zones) > 0 {
+		fmt.Printf("\nExcess Zone Factors (%d zones)\n", len(zones))
+		excessZoneFactorStats := stats.Stats{}
+		excessZoneFactorStats.UpdateArray(excessZoneFactorData)
+		fmt.Printf("  Min: %.4f | Max: %.4f | Mean: %.4f | Variance: %.4f\n", excessZoneFactorStats.Min(), excessZoneFactorStats.Max(), excessZoneFactorStats.Mean(), excessZoneFactorStats.PopulationVariance())
+	}
+
 	fmt.Println("\n*** REP STATISTICS ***")
 	memoryData := []float64{}
 	diskData := []float64{}
 	containersData := []float64{}
+	numIdle := 0
 	maxGuidLength := 0
 	for _, repGuid := range representatives {
 		if len(repGuid) > maxGuidLength {
@@ -130,6 +170,9 @@ func PrintReport(
 
 		memory, disk := 0, 0
 		containersData = append(containersData, float64(len(instances)))
+		if len(instances) == 0 {
+			numIdle += 1
+		}
 
 		availableColors := []string{"red", "cyan", "yellow", "gray", "purple", "green"}
 		colorLookup := map[string]string{"red": redColor, "green": greenColor, "cyan": cyanColor, "yellow": yellowColor, "gray": lightGrayColor, "purple": purpleColor}
@@ -160,7 +203,7 @@ func PrintReport(
 			instanceString += strings.Repeat(colorLookup[col]+"○"+defaultStyle, originalCounts[col])
 			instanceString += strings.Repeat(colorLookup[col]+"●"+defaultStyle, newCounts[col])
 		}
-		instanceString += strings.Repeat(grayColor+"○"+defaultStyle, client.TotalResources(repGuid).Containers-len(instances))
+		instanceString += strings.Repeat(grayColor+"○"+defaultStyle, client.TotalResources(repGuid)[auctiontypes.Containers]-len(instances))
 
 		containerHistogramLines = append(containerHistogramLines, fmt.Sprintf("  %s: %s", fmt.Sprintf(guidFormat, repGuid), instanceString))
 	}
@@ -179,6 +222,7 @@ func PrintReport(
 	containersStats := stats.Stats{}
 	containersStats.UpdateArray(containersData)
 	fmt.Printf("  Min: %.0f | Max: %.0f | Total: %.0f | Mean: %.2f | Variance: %.2f\n", containersStats.Min(), containersStats.Max(), containersStats.Sum(), containersStats.Mean(), containersStats.PopulationVariance())
+	fmt.Printf("  Idle Reps: %d of %d\n", numIdle, len(representatives))
 	fmt.Println("  Distribution:")
 	for _, histogramLine := range containerHistogramLines {
 		fmt.Println(histogramLine)
@@ -1,6 +1,7 @@
 package visualization
 
 import (
+	"math"
 	"sort"
 	"time"
 
@@ -12,6 +13,7 @@ type Report struct {
 	RepGuids                     []string
 	AuctionResults               []auctiontypes.StartAuctionResult
 	InstancesByRep               map[string][]auctiontypes.SimulatedInstance
+	ZonesByRep                   map[string]string
 	AuctionDuration              time.Duration
 	auctionedInstancesByInstGuid map[string]bool
 }
@@ -98,6 +100,64 @@ func (r *Report) DistributionScore() float64 {
 	return stats.StatsPopulationStandardDeviation(memoryCounts) / stats.StatsMean(memoryCounts)
 }
 
+// the reps running nothing at all, which packing instances tightly should maximize
+func (r *Report) NIdleReps() int {
+	numIdle := 0
+	for _, repGuid := range r.RepGuids {
+		if len(r.InstancesByRep[repGuid]) == 0 {
+			numIdle += 1
+		}
+	}
+
+	return numIdle
+}
+
+func (r *Report) NZones() int {
+	zones := map[string]bool{}
+	for _, zone := range r.ZonesByRep {
+		if zone != "" {
+			zones[zone] = true
+		}
+	}
+
+	return len(zones)
+}
+
+// for each process: the most instances in any one zone over the most a perfectly balanced spread would put there
+func (r *Report) ExcessZoneFactorStats() Stat {
+	nZones := r.NZones()
+	if nZones == 0 {
+		return Stat{}
+	}
+
+	instancesByProcessAndZone := map[string]map[string]int{}
+	totalInstancesForProcess := map[string]int{}
+	for repGuid, instances := range r.InstancesByRep {
+		zone := r.ZonesByRep[repGuid]
+		for _, instance := range instances {
+			if instancesByProcessAndZone[instance.ProcessGuid] == nil {
+				instancesByProcessAndZone[instance.ProcessGuid] = map[string]int{}
+			}
+			instancesByProcessAndZone[instance.ProcessGuid][zone] += 1
+			totalInstancesForProcess[instance.ProcessGuid] += 1
+		}
+	}
+
+	excessZoneFactors := []float64{}
+	for processGuid, instancesByZone := range instancesByProcessAndZone {
+		maxInstancesInZone := 0
+		for _, n := range instancesByZone {
+			if n > maxInstancesInZone {
+				maxInstancesInZone = n
+			}
+		}
+		expectedMaxInstancesInZone := math.Ceil(float64(totalInstancesForProcess[processGuid]) / float64(nZones))
+		excessZoneFactors = append(excessZoneFactors, float64(maxInstancesInZone)/expectedMaxInstancesInZone)
+	}
+
+	return NewStat(excessZoneFactors)
+}
+
 func (r *Report) AuctionsPerSecond() float64 {
 	return float64(r.NAuctions()) / r.AuctionDuration.Seconds()
 }
@@ -140,6 +200,15 @@ func FetchAndSortInstances(client auctiontypes.SimulationRepPoolClient, repGuids
 	return instancesByRepGuid
 }
 
+func FetchZones(client auctiontypes.SimulationRepPoolClient, repGuids []string) map[string]string {
+	zonesByRepGuid := map[string]string{}
+	for _, repGuid := range repGuids {
+		zonesByRepGuid[repGuid] = client.Zone(repGuid)
+	}
+
+	return zonesByRepGuid
+}
+
 type ByProcessGuid []auctiontypes.SimulatedInstance
 
 func (a ByProcessGuid) Len() int           { return len(a) }
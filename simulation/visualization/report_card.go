@@ -147,6 +147,7 @@ func (r *SVGReport) drawText(report *Report, y int) {
 	commStats := report.CommStats()
 	bidStats := report.BiddingTimeStats()
 	waitStats := report.WaitTimeStats()
+	zoneStats := report.ExcessZoneFactorStats()
 
 	missing := ""
 	missingInstances := report.NMissingInstances()
@@ -157,7 +158,8 @@ func (r *SVGReport) drawText(report *Report, y int) {
 	lines := []string{
 		fmt.Sprintf("%d over %d Reps %s", report.NAuctions(), report.NReps(), missing),
 		fmt.Sprintf("%.2fs (%.2f a/s)", report.AuctionDuration.Seconds(), report.AuctionsPerSecond()),
-		fmt.Sprintf("Dist: %.3f => %.3f", report.InitialDistributionScore(), report.DistributionScore()),
+		fmt.Sprintf("Dist: %.3f => %.3f | Idle: %d", report.InitialDistributionScore(), report.DistributionScore(), report.NIdleReps()),
+		fmt.Sprintf("Zones: %d | %.2f ± %.2f | %.2f", report.NZones(), zoneStats.Mean, zoneStats.StdDev, zoneStats.Max),
 		fmt.Sprintf("%.0f Comm | %.1f ± %.1f | %.0f - %.0f", commStats.Total, commStats.Mean, commStats.StdDev, commStats.Min, commStats.Max),
 	}
 	statLines := []string{